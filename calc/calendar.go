@@ -0,0 +1,58 @@
+package calc
+
+import "time"
+
+// CalendarYearLoan is the interest and principal paid on the loan within one calendar year
+type CalendarYearLoan struct {
+	Year      int
+	Interest  float64
+	Principal float64
+}
+
+// HasLoanStart reports whether the schedule is tied to calendar months
+func (s *Scenario) HasLoanStart() bool {
+	return !s.LoanStart.IsZero()
+}
+
+// MonthDate returns the calendar month of the given 1-based month of the schedule, or the
+// zero time without a loan start
+func (s *Scenario) MonthDate(month int) time.Time {
+	if !s.HasLoanStart() {
+		return time.Time{}
+	}
+	start := time.Date(s.LoanStart.Year(), s.LoanStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.AddDate(0, month-1, 0)
+}
+
+// LoanByCalendarYear totals the interest and principal paid in each calendar year of the
+// schedule, for years with any payments. It returns nil without a loan start.
+func (s *Scenario) LoanByCalendarYear() []CalendarYearLoan {
+	if !s.HasLoanStart() {
+		return nil
+	}
+
+	var years []CalendarYearLoan
+	for monthIndex := range s.remainingLoanBalance {
+		entry := s.amortizationEntry(monthIndex)
+		if entry.InterestPaid == 0 && entry.PrincipalPaid == 0 {
+			continue
+		}
+		year := entry.Date.Year()
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, CalendarYearLoan{Year: year})
+		}
+		years[len(years)-1].Interest += entry.InterestPaid
+		years[len(years)-1].Principal += entry.PrincipalPaid
+	}
+	return years
+}
+
+// InterestPaidInYear returns the loan interest paid within the given calendar (tax) year
+func (s *Scenario) InterestPaidInYear(year int) float64 {
+	for _, y := range s.LoanByCalendarYear() {
+		if y.Year == year {
+			return y.Interest
+		}
+	}
+	return 0
+}
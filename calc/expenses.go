@@ -0,0 +1,233 @@
+package calc
+
+// RentExpensesResult holds the renting costs of the SELL scenario for the last year of a period
+type RentExpensesResult struct {
+	MonthlyRent     float64 // Rent paid during the year
+	RentCosts       float64 // Annual rent costs for the year
+	Total           float64 // Sum for the year
+	CumulativeTotal float64 // All renting costs up to the period, net of the recoverable deposit
+}
+
+// KeepExpensesResult holds the ownership costs of the KEEP scenario for one year
+type KeepExpensesResult struct {
+	LoanPayment float64 // Loan payments during the year (stops after the loan term)
+	Insurance   float64 // Annual tax & insurance (inflated), plus any value-based property tax and council tax
+	OtherCosts  float64 // Other annual costs + monthly expenses, reserve and utilities (inflated)
+	Total       float64
+}
+
+// OwnershipCostsResult breaks down where the money goes when owning over a span, next to the rent paid instead
+type OwnershipCostsResult struct {
+	LoanPayments float64 // Principal plus interest, including any recast lump sum
+	Interest     float64
+	TaxInsurance float64 // Annual tax & insurance
+	Maintenance  float64 // Other annual costs plus monthly expenses
+	Total        float64 // Loan payments plus recurring ownership costs
+	RentPaid     float64 // Rent, renting costs and the broker fee over the same span (excluding the refundable deposit)
+}
+
+// periodYear returns the 0-based year that a period of the given months ends in
+func periodYear(months int) int {
+	years := (months - 1) / 12
+	if years < 0 {
+		years = 0
+	}
+	return years
+}
+
+// SellRentExpensesAt calculates the SELL scenario's renting costs for the last year of the period
+// along with the cumulative total (including the initial deposit and broker fee, and the recoverable
+// deposit at the end)
+func (s *Scenario) SellRentExpensesAt(months int) RentExpensesResult {
+	var result RentExpensesResult
+
+	// Annual expenses for the year this period ends in
+	year := periodYear(months)
+	inflatedMonthlyRent := s.MonthlyRent * s.rentFactor(year)
+	result.MonthlyRent = inflatedMonthlyRent * 12
+	result.RentCosts = s.AnnualRentCosts * s.inflationFactor(year)
+	result.Total = result.MonthlyRent + result.RentCosts
+
+	// Cumulative monthly rent
+	cumulativeMonthlyRent := 0.0
+	for i := s.RentFreeMonths; i < months; i++ {
+		cumulativeMonthlyRent += s.MonthlyRent * s.rentFactor(i/12)
+	}
+
+	// Cumulative annual rent costs, prorating a partial final year
+	cumulativeAnnualRentCosts := 0.0
+	fullYears := months / 12
+	for year := 0; year < fullYears; year++ {
+		cumulativeAnnualRentCosts += s.AnnualRentCosts * s.inflationFactor(year)
+	}
+	if months%12 > 0 {
+		inflatedAnnualCost := s.AnnualRentCosts * s.inflationFactor(fullYears)
+		cumulativeAnnualRentCosts += inflatedAnnualCost * float64(months%12) / 12.0
+	}
+
+	// Cumulative total includes deposit and broker fee at start and recoverable deposit at end
+	result.CumulativeTotal = s.RentDeposit + s.BrokerFee + cumulativeMonthlyRent + cumulativeAnnualRentCosts - (s.RentDeposit * RecoverableDepositFraction)
+
+	return result
+}
+
+// SellRentingExpenditureAt returns the deposit plus all monthly renting costs, net of the recoverable deposit
+func (s *Scenario) SellRentingExpenditureAt(months int) float64 {
+	cumulativeRentExpenses := s.rentingExpenditure[s.prefixIndex(months)] + s.leaseBreakPenaltyAt(months)
+	// Subtract recoverable deposit
+	cumulativeRentExpenses -= s.RentDeposit * RecoverableDepositFraction
+
+	return cumulativeRentExpenses
+}
+
+// BuyingCostAt returns the buying cost paid in the given 1-based month
+func (s *Scenario) BuyingCostAt(month int) float64 {
+	return s.monthlyBuyingCosts[s.monthIndex(month)]
+}
+
+// RentingCostAt returns the renting cost paid in the given 1-based month
+func (s *Scenario) RentingCostAt(month int) float64 {
+	return s.monthlyRentingCosts[s.monthIndex(month)]
+}
+
+// CumulativeBuyingCosts returns the sum of the monthly buying costs over the given number of months
+func (s *Scenario) CumulativeBuyingCosts(months int) float64 {
+	return s.cumulativeBuyingCosts[s.prefixIndex(months)]
+}
+
+// EffectiveMonthlyOwnershipCost returns the imputed rent of owning: the average monthly
+// buying cost over the first year, minus the principal paid down and the expected
+// appreciation, since both stay with the owner. It's directly comparable to the monthly rent.
+// First-year capital improvements are left out: their cost goes into the home's value rather
+// than being spent. Taxes are as in the monthly buying costs, which only net the depreciation
+// deduction of an investment property.
+func (s *Scenario) EffectiveMonthlyOwnershipCost() float64 {
+	months := min(12, s.Months())
+	if months <= 0 {
+		return 0
+	}
+	improvements := s.ImprovementsBefore(months)
+	cost := s.CumulativeBuyingCosts(months) - improvements
+	cost -= s.cumulativePrincipalPaid[s.monthIndex(months)]
+	cost -= s.homeValueAt(s.PurchasePrice, months) - s.PurchasePrice - improvements
+	return cost / float64(months)
+}
+
+// Debt-to-income guidelines (%) for the affordability check: lenders look for housing costs
+// within FrontEndDTILimit of gross income, and all debt payments within BackEndDTILimit
+const (
+	FrontEndDTILimit = 28.0
+	BackEndDTILimit  = 36.0
+)
+
+// FrontEndDTI returns the first month's housing cost (loan payment plus recurring ownership
+// costs) as a % of gross monthly income, or 0 when the income isn't given
+func (s *Scenario) FrontEndDTI() float64 {
+	if s.GrossMonthlyIncome <= 0 {
+		return 0
+	}
+	return s.TotalMonthlyBuyingCost / s.GrossMonthlyIncome * 100
+}
+
+// OwnershipCostsOver totals the costs of owning over the given number of months
+func (s *Scenario) OwnershipCostsOver(months int) OwnershipCostsResult {
+	var result OwnershipCostsResult
+
+	if months > 0 {
+		loan := s.AmortizationAt(months)
+		result.LoanPayments = loan.CumulativePrincipal + loan.CumulativeInterest
+		result.Interest = loan.CumulativeInterest
+	}
+
+	// Recurring costs are equal across the months of a year
+	for i := 0; i < months; i++ {
+		ye := s.KeepExpensesAt(i + 1)
+		result.TaxInsurance += ye.Insurance / 12
+		result.Maintenance += ye.OtherCosts / 12
+	}
+
+	result.Total = result.LoanPayments + result.TaxInsurance + result.Maintenance
+	// The deposit comes back at move-out; the broker fee is spent like rent, so it stays in
+	result.RentPaid = s.rentingExpenditure[s.prefixIndex(months)] - s.RentDeposit
+	return result
+}
+
+// KeepExpensesAt returns the KEEP scenario's ownership costs for the year the period ends in
+func (s *Scenario) KeepExpensesAt(months int) KeepExpensesResult {
+	year := periodYear(months)
+	if year >= len(s.keepYearlyExpenses) {
+		year = len(s.keepYearlyExpenses) - 1
+	}
+	return s.keepYearlyExpenses[year]
+}
+
+// calculateKeepYearlyExpenses pre-calculates annual ownership expenses for each year,
+// with one extra year to cover the last period fully
+func (s *Scenario) calculateKeepYearlyExpenses(projectedMonths int) {
+	maxMonths := projectedMonths + 12
+	numYears := maxMonths / 12
+	s.keepYearlyExpenses = make([]KeepExpensesResult, numYears)
+
+	currentInsurance := s.AnnualInsurance / 12
+	currentOtherCosts := s.AnnualTaxes / 12
+	currentMonthlyExp := s.MonthlyExpenses + s.ReplacementReserve + s.BuyUtilities
+
+	for year := 0; year < numYears; year++ {
+		var ye KeepExpensesResult
+
+		// Calculate for 12 months of this year
+		for month := 0; month < 12; month++ {
+			monthIndex := year*12 + month
+
+			if monthIndex >= maxMonths {
+				break
+			}
+
+			// Loan payment only while a loan is being paid
+			if s.loanPaymentDue(monthIndex) {
+				ye.LoanPayment += s.loanPaymentAt(monthIndex)
+			}
+
+			// Ownership costs change with the home at the re-buy
+			if s.hasRebuy() && monthIndex == s.rebuyMonth() {
+				currentInsurance *= s.rebuyCostScale()
+				currentOtherCosts *= s.rebuyCostScale()
+				currentMonthlyExp *= s.rebuyCostScale()
+			}
+
+			// Recurring expenses
+			ye.Insurance += currentInsurance
+			if s.PropertyTaxRate > 0 {
+				ye.Insurance += s.PropertyTaxAt(year) / 12
+			}
+			if s.CouncilTax != 0 {
+				councilTax := s.CouncilTaxAt(year)
+				if s.ownsRebuyHome(monthIndex + 1) {
+					councilTax *= s.rebuyCostScale()
+				}
+				ye.Insurance += councilTax
+			}
+			ye.OtherCosts += currentOtherCosts + currentMonthlyExp
+			if s.GroundRent != 0 || s.ServiceCharge != 0 {
+				leasehold := s.LeaseholdChargesAt(year)
+				if s.ownsRebuyHome(monthIndex + 1) {
+					leasehold *= s.rebuyCostScale()
+				}
+				ye.OtherCosts += leasehold
+			}
+		}
+
+		ye.Total = ye.LoanPayment + ye.Insurance + ye.OtherCosts
+		s.keepYearlyExpenses[year] = ye
+
+		// Apply this year's inflation for next year
+		inflationRate := s.inflationRate(year)
+		if s.capsTaxLine() {
+			currentInsurance *= (1 + s.TaxReassessmentCap/100)
+		} else {
+			currentInsurance *= (1 + inflationRate/100)
+		}
+		currentOtherCosts *= (1 + inflationRate/100)
+		currentMonthlyExp *= (1 + inflationRate/100)
+	}
+}
@@ -0,0 +1,46 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+// A re-buy loan keeps being paid after the original loan's term would have ended
+func TestKeepExpensesRebuyLoanPastOriginalTerm(t *testing.T) {
+	s := NewScenario(Inputs{
+		PurchasePrice: 400000,
+		Downpayment:   100000,
+		LoanAmount:    300000,
+		AnnualRate:    6,
+		LoanMonths:    360,
+		RebuyYear:     5,
+		RebuyPrice:    800000,
+	})
+
+	// Year 31 is past the original 30-year term but within the re-buy loan's
+	got := s.keepYearlyExpenses[30].LoanPayment
+	if want := s.RebuyPayment * 12; s.RebuyPayment == 0 || math.Abs(got-want) > 1e-6 {
+		t.Errorf("loan payments in year 31 = %v, want %v", got, want)
+	}
+}
+
+func TestEffectiveMonthlyOwnershipCost(t *testing.T) {
+	s := NewScenario(Inputs{
+		PurchasePrice:       100000,
+		Downpayment:         40000,
+		LoanAmount:          60000,
+		AnnualRate:          0,
+		LoanMonths:          120,
+		AnnualInsurance:     1200,
+		AppreciationRates:   []float64{5},
+		CapitalImprovements: []float64{6000},
+	})
+
+	// Year 1 costs 6,000 of interest-free loan payments (all principal) and 1,200 of insurance;
+	// the 6,000 improvement isn't counted. The home grows to (100,000 + 6,000) * 1.05 = 111,300,
+	// so it appreciates 5,300 beyond the improvement: (7,200 - 6,000 - 5,300) / 12 = -341.67
+	want := -4100.0 / 12
+	if got := s.EffectiveMonthlyOwnershipCost(); math.Abs(got-want) > 1e-6 {
+		t.Errorf("EffectiveMonthlyOwnershipCost() = %.4f, want %.4f", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package calc
+
+import (
+	"math"
+	"time"
+)
+
+// AmortizationEntry is the state of the loan after a given month's payment
+type AmortizationEntry struct {
+	Month               int // 1-based month number
+	PrincipalPaid       float64
+	InterestPaid        float64
+	CumulativePrincipal float64
+	CumulativeInterest  float64
+	Balance             float64   // Remaining loan balance after the payment
+	Date                time.Time // Calendar month of the payment (zero without a loan start)
+}
+
+// MonthlyPayment calculates the monthly payment using the amortization formula
+// M = P * [r(1+r)^n] / [(1+r)^n - 1]
+func MonthlyPayment(principal, monthlyRate float64, months int) float64 {
+	if monthlyRate == 0 {
+		return principal / float64(months)
+	}
+
+	factor := math.Pow(1+monthlyRate, float64(months))
+	monthlyPayment := principal * (monthlyRate * factor) / (factor - 1)
+	return monthlyPayment
+}
+
+// PeriodRate converts a nominal annual loan rate (%) to the rate per payment period. With
+// semi-annual compounding, as on Canadian fixed mortgages, the rate compounds twice a year
+// and the period rate is the equivalent effective rate rather than a simple fraction.
+func PeriodRate(annualRate float64, paymentsPerYear int, semiAnnual bool) float64 {
+	if semiAnnual {
+		return math.Pow(1+annualRate/100/2, 2/float64(paymentsPerYear)) - 1
+	}
+	return annualRate / 100 / float64(paymentsPerYear)
+}
+
+// RemainingBalance simulates monthsElapsed payments on a loan and returns the balance left
+func RemainingBalance(principal, monthlyRate float64, totalMonths, monthsElapsed int) float64 {
+	payment := MonthlyPayment(principal, monthlyRate, totalMonths)
+
+	remainingBalance := principal
+	for i := 0; i < monthsElapsed; i++ {
+		interestPayment := remainingBalance * monthlyRate
+		principalPayment := payment - interestPayment
+		remainingBalance -= principalPayment
+	}
+	return remainingBalance
+}
+
+// monthlyInterestRate returns the interest rate applied at the annual rate (%) in the given
+// 0-based month of the schedule: a twelfth of the annual rate (30/360), or with ActualDayCount,
+// the days of the month before the payment (interest is paid in arrears) over 365
+func (s *Scenario) monthlyInterestRate(month int, annualRate float64) float64 {
+	if !s.ActualDayCount || !s.HasLoanStart() {
+		return PeriodRate(annualRate, 12, s.SemiAnnualCompounding)
+	}
+	paymentDate := s.MonthDate(month + 1)
+	days := time.Date(paymentDate.Year(), paymentDate.Month(), 0, 0, 0, 0, 0, time.UTC).Day()
+	return annualRate / 100 * float64(days) / 365
+}
+
+// AmortizationSchedule returns the loan state after every month of the projection
+func (s *Scenario) AmortizationSchedule() []AmortizationEntry {
+	schedule := make([]AmortizationEntry, len(s.remainingLoanBalance))
+	for i := range schedule {
+		schedule[i] = s.amortizationEntry(i)
+	}
+	return schedule
+}
+
+// AmortizationAt returns the loan state after the given number of months
+func (s *Scenario) AmortizationAt(months int) AmortizationEntry {
+	return s.amortizationEntry(s.monthIndex(months))
+}
+
+// PayoffMonth returns the 1-based month the first loan is paid off in: the end of the term,
+// or earlier when offset savings cut the interest
+func (s *Scenario) PayoffMonth() int {
+	end := min(s.LoanMonths, s.Months())
+	if s.hasRebuy() {
+		end = min(end, s.rebuyMonth())
+	}
+	for i := 0; i < end; i++ {
+		if s.remainingLoanBalance[i] <= 0 {
+			return i + 1
+		}
+	}
+	return s.LoanMonths
+}
+
+// loanPaymentDue reports whether a loan payment falls in the given 0-based month: within the
+// term of the loan in effect (the re-buy loan's runs from the re-buy), and until an offset loan
+// is paid off early
+func (s *Scenario) loanPaymentDue(month int) bool {
+	start, end := 0, s.LoanMonths
+	if s.hasRebuy() {
+		if month >= s.rebuyMonth() {
+			start, end = s.rebuyMonth(), s.rebuyMonth()+s.LoanMonths
+		} else {
+			end = min(end, s.rebuyMonth())
+		}
+	}
+	if month >= end {
+		return false
+	}
+	// Past the projected schedule the loan is still within its term
+	if month == start || month > len(s.remainingLoanBalance) {
+		return true
+	}
+	return s.remainingLoanBalance[month-1] > 0
+}
+
+func (s *Scenario) amortizationEntry(monthIndex int) AmortizationEntry {
+	entry := AmortizationEntry{
+		Month:               monthIndex + 1,
+		CumulativePrincipal: s.cumulativePrincipalPaid[monthIndex],
+		CumulativeInterest:  s.cumulativeInterestPaid[monthIndex],
+		Balance:             s.remainingLoanBalance[monthIndex],
+		Date:                s.MonthDate(monthIndex + 1),
+	}
+	if monthIndex > 0 {
+		entry.PrincipalPaid = entry.CumulativePrincipal - s.cumulativePrincipalPaid[monthIndex-1]
+		entry.InterestPaid = entry.CumulativeInterest - s.cumulativeInterestPaid[monthIndex-1]
+	} else {
+		entry.PrincipalPaid = entry.CumulativePrincipal
+		entry.InterestPaid = entry.CumulativeInterest
+	}
+	return entry
+}
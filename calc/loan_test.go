@@ -0,0 +1,90 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMonthlyPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   float64
+		monthlyRate float64
+		months      int
+		want        float64
+	}{
+		// No interest: the principal is spread evenly over the term
+		{"zero rate", 120000, 0, 360, 333.333333},
+		// r = 1 (100% a month): P * 2^12 / (2^12 - 1) = 4095 * 4096 / 4095
+		{"very high rate", 4095, 1, 12, 4096},
+		// A single payment repays the principal plus one month of interest
+		{"one month", 1000, 0.01, 1, 1010},
+		// 300K at 6% over 30 years: 300000 * 0.005 * 1.005^360 / (1.005^360 - 1)
+		{"30-year at 6%", 300000, 0.06 / 12, 360, 1798.65},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MonthlyPayment(tt.principal, tt.monthlyRate, tt.months)
+			if math.Abs(got-tt.want) > 0.005 {
+				t.Errorf("MonthlyPayment(%v, %v, %d) = %.4f, want %.2f", tt.principal, tt.monthlyRate, tt.months, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScenarioPaysOffLoan(t *testing.T) {
+	s := NewScenario(Inputs{
+		PurchasePrice: 400000,
+		Downpayment:   100000,
+		LoanAmount:    300000,
+		AnnualRate:    6,
+		LoanMonths:    360,
+	})
+
+	if math.Abs(s.MonthlyLoanPayment-1798.65) > 0.005 {
+		t.Errorf("MonthlyLoanPayment = %.4f, want 1798.65", s.MonthlyLoanPayment)
+	}
+	end := s.AmortizationAt(s.LoanMonths)
+	if math.Abs(end.Balance) > 1e-6 {
+		t.Errorf("balance after %d months = %v, want 0", s.LoanMonths, end.Balance)
+	}
+	if math.Abs(end.CumulativePrincipal-300000) > 1e-6 {
+		t.Errorf("principal paid after %d months = %v, want 300000", s.LoanMonths, end.CumulativePrincipal)
+	}
+}
+
+// The overpayment cap limits the extra principal the recast applies and charges the excess
+func TestOverpaymentCap(t *testing.T) {
+	inputs := Inputs{
+		PurchasePrice: 400000,
+		Downpayment:   100000,
+		LoanAmount:    300000,
+		AnnualRate:    6,
+		LoanMonths:    360,
+	}
+	uncapped := NewScenario(inputs)
+
+	inputs.RecastMonth = 12
+	inputs.RecastAmount = 50000
+	inputs.OverpaymentCapPct = 10
+	inputs.OverpaymentPenaltyPct = 2
+	s := NewScenario(inputs)
+
+	// The recast falls in the first loan year, so the cap is 10% of the 300K loan: 30K is
+	// applied, and the 20K excess pays a 2% charge of 400
+	if math.Abs(s.OverpaymentAllowance-30000) > 1e-6 {
+		t.Errorf("OverpaymentAllowance = %v, want 30000", s.OverpaymentAllowance)
+	}
+	if math.Abs(s.OverpaymentExcess-20000) > 1e-6 {
+		t.Errorf("OverpaymentExcess = %v, want 20000", s.OverpaymentExcess)
+	}
+	if math.Abs(s.OverpaymentPenalty-400) > 1e-6 {
+		t.Errorf("OverpaymentPenalty = %v, want 400", s.OverpaymentPenalty)
+	}
+
+	want := uncapped.AmortizationAt(12).Balance - 30000
+	if got := s.AmortizationAt(12).Balance; math.Abs(got-want) > 1e-6 {
+		t.Errorf("balance after the recast month = %.2f, want %.2f", got, want)
+	}
+}
@@ -0,0 +1,400 @@
+package calc
+
+import "math"
+
+// NetWorthResult is the buyer's position after a given number of months
+type NetWorthResult struct {
+	AssetValue       float64 // Appreciated value of the asset
+	TotalExpenditure float64 // Downpayment plus all monthly buying costs
+	NetWorth         float64 // Net sale proceeds if selling is included, otherwise equity
+}
+
+// SaleResult breaks down the proceeds of selling after a given number of months
+type SaleResult struct {
+	SalePrice    float64
+	SellingCosts float64 // Agent commission, staging costs (inflated to the sale date) and any transfer tax
+	LoanPayoff   float64
+	CapitalGains float64 // Gains after deducting selling costs
+	Tax          float64 // Capital gains tax plus depreciation recapture
+	NetProceeds  float64
+}
+
+// RentingResult is the renter's position after a given number of months
+type RentingResult struct {
+	CumulativeSavings float64 // Raw difference in costs (buying - renting) without investment growth
+	MarketReturn      float64 // Investment growth on the savings
+	NetWorth          float64 // Investment value plus the recoverable deposit
+}
+
+// ExpenditureResult is the total cash spent on each side after a given number of months
+type ExpenditureResult struct {
+	Buying     float64 // Downpayment plus all monthly buying costs
+	Renting    float64 // Deposit plus all monthly renting costs
+	Difference float64 // Buying - Renting
+}
+
+// KeepPositionResult is the KEEP scenario's investment position after a given number of months
+type KeepPositionResult struct {
+	InvestmentValue float64 // Value of invested income
+	RealCosts       float64 // Cumulative out-of-pocket costs not covered by income
+	NetPosition     float64 // Investment value minus real costs
+}
+
+// appreciatedValue compounds the appreciation rates year by year on top of startingPrice,
+// or adds them as fixed dollar amounts per year in dollar mode. Capital improvements
+// are added at the start of the year they're made and appreciate from then on.
+func (s *Scenario) appreciatedValue(startingPrice float64, months int) float64 {
+	value := startingPrice
+	years := months / 12
+	remainingMonths := months % 12
+
+	if s.AppreciationInDollars {
+		for year := 0; year < years; year++ {
+			value += s.improvementAt(year)
+			value += s.appreciationRate(year)
+		}
+		// A partial year adds a prorated share of that year's amount
+		if remainingMonths > 0 {
+			value += s.improvementAt(years)
+			value += s.appreciationRate(years) * float64(remainingMonths) / 12.0
+		}
+		return value
+	}
+
+	// Apply each year's rate
+	for year := 0; year < years; year++ {
+		value += s.improvementAt(year)
+		rateIndex := year
+		if rateIndex >= len(s.AppreciationRates) {
+			rateIndex = len(s.AppreciationRates) - 1 // Use last rate for all future years
+		}
+		value *= (1 + s.AppreciationRates[rateIndex]/100)
+	}
+
+	// Apply partial year if there are remaining months
+	if remainingMonths > 0 {
+		value += s.improvementAt(years)
+		rateIndex := years
+		if rateIndex >= len(s.AppreciationRates) {
+			rateIndex = len(s.AppreciationRates) - 1
+		}
+		partialYearFactor := math.Pow(1+s.AppreciationRates[rateIndex]/100, float64(remainingMonths)/12.0)
+		value *= partialYearFactor
+	}
+
+	return value
+}
+
+// improvementAt returns the capital improvements made at the start of the given 0-based year
+// (to the first home only when re-buying)
+func (s *Scenario) improvementAt(year int) float64 {
+	if s.hasRebuy() && year >= s.RebuyYear {
+		return 0
+	}
+	if year < len(s.CapitalImprovements) {
+		return s.CapitalImprovements[year]
+	}
+	return 0
+}
+
+// ImprovementsBefore returns the total capital improvements made before the given number of months
+func (s *Scenario) ImprovementsBefore(months int) float64 {
+	total := 0.0
+	for year := 0; year*12 < months; year++ {
+		total += s.improvementAt(year)
+	}
+	return total
+}
+
+// appreciationRate returns the appreciation for the given 0-based year
+func (s *Scenario) appreciationRate(year int) float64 {
+	if year >= len(s.AppreciationRates) {
+		year = len(s.AppreciationRates) - 1 // Use last rate for all future years
+	}
+	return s.AppreciationRates[year]
+}
+
+// NetWorthAt calculates the asset value, total expenditure, and net worth of buying after the given months
+func (s *Scenario) NetWorthAt(months int) NetWorthResult {
+	var result NetWorthResult
+
+	// Calculate asset value by compounding each year's appreciation rate
+	result.AssetValue = s.homeValueAt(s.PurchasePrice, months)
+
+	// Total expenditure is the downpayment plus all monthly costs
+	result.TotalExpenditure = s.buyingExpenditure[s.prefixIndex(months)]
+
+	// Calculate net worth
+	if s.IncludeSelling {
+		// If selling is enabled, use net proceeds after selling costs
+		result.NetWorth = s.SaleProceedsAt(months).NetProceeds
+	} else {
+		// Otherwise, just asset value minus loan balance
+		loanBalance := s.remainingLoanBalance[s.monthIndex(months)]
+		result.NetWorth = result.AssetValue - loanBalance
+	}
+
+	return result
+}
+
+// LoanToValueAt returns the loan balance as a % of the appreciated asset value after the
+// given number of months (0 once the loan is paid off). Equity is the remaining 100%.
+func (s *Scenario) LoanToValueAt(months int) float64 {
+	startingPrice := s.PurchasePrice
+	if s.CurrentMarketValue > 0 {
+		startingPrice = s.CurrentMarketValue
+	}
+	value := s.homeValueAt(startingPrice, months)
+	balance := s.remainingLoanBalance[s.monthIndex(months)]
+	if balance <= 0 || value <= 0 {
+		return 0
+	}
+	return balance / value * 100
+}
+
+// CashInvestedAt returns the cash put into the home itself after the given number of months:
+// the upfront cash (see UpfrontCash), principal paid and capital improvements. Interest,
+// taxes and upkeep are the cost of living there, like rent, so they're left out.
+func (s *Scenario) CashInvestedAt(months int) float64 {
+	invested := s.UpfrontCash() + s.ImprovementsBefore(months)
+	if months > 0 {
+		invested += s.cumulativePrincipalPaid[s.monthIndex(months)]
+	}
+	return invested
+}
+
+// MonthsToWhole returns the first month in which selling would return at least the cash
+// invested in the home (CashInvestedAt), i.e. when you could sell and walk away whole. The
+// search stops at a re-buy, since the cash then moves into the next home.
+func (s *Scenario) MonthsToWhole() (int, bool) {
+	last := s.Months()
+	if s.hasRebuy() {
+		last = min(last, s.rebuyMonth())
+	}
+	for months := 1; months <= last; months++ {
+		if s.SaleProceedsAt(months).NetProceeds >= s.CashInvestedAt(months) {
+			return months, true
+		}
+	}
+	return 0, false
+}
+
+// CrossoverMonth scans month by month up to the given months for the first month in which
+// the side ahead (renting or buying) changes. It reports false if the lead never changes.
+func (s *Scenario) CrossoverMonth(months int) (int, bool) {
+	months = min(months, s.Months())
+	lead := 0.0
+	for m := 1; m <= months; m++ {
+		difference := s.RentingNetWorthAt(m).NetWorth - s.NetWorthAt(m).NetWorth
+		if lead == 0 {
+			lead = difference
+			continue
+		}
+		if (lead > 0 && difference <= 0) || (lead < 0 && difference >= 0) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// SaleProceedsAt calculates the proceeds from selling after the given number of months
+func (s *Scenario) SaleProceedsAt(months int) SaleResult {
+	var result SaleResult
+
+	// Determine starting price for appreciation calculation
+	// SELL vs KEEP: start from current market value
+	// BUY vs RENT: start from original purchase price
+	startingPrice := s.PurchasePrice
+	if s.CurrentMarketValue > 0 {
+		startingPrice = s.CurrentMarketValue
+	}
+
+	// Calculate asset value (sale price) by compounding appreciation rates
+	result.SalePrice = s.homeValueAt(startingPrice, months)
+
+	// Calculate agent commission
+	agentFee := result.SalePrice * (s.AgentCommission / 100)
+
+	// Combine agent commission and staging costs, which rise with inflation over the holding period
+	result.SellingCosts = agentFee + s.StagingCosts*s.inflationFactor(months/12) + s.saleTransferTax(result.SalePrice)
+
+	// Get remaining loan balance
+	result.LoanPayoff = s.remainingLoanBalance[s.monthIndex(months)]
+
+	// Calculate capital gains (selling costs are deductible, improvements and the purchase
+	// transfer tax raise the basis)
+	heldMonths := months
+	if s.ownsRebuyHome(months) {
+		result.CapitalGains = result.SalePrice - s.RebuyPrice - result.SellingCosts
+		heldMonths = months - s.rebuyMonth()
+	} else {
+		result.CapitalGains = result.SalePrice - s.PurchasePrice - s.PurchaseTransferTax() + s.SellerConcessions - s.ImprovementsBefore(months) - result.SellingCosts
+	}
+
+	// Get tax-free limit for the holding period
+	// For year 1 (12 months), use index 0; for year 2 (24 months), use index 1, etc.
+	taxFreeLimitIndex := heldMonths/12 - 1
+	if taxFreeLimitIndex < 0 {
+		taxFreeLimitIndex = 0
+	}
+	if taxFreeLimitIndex >= len(s.TaxFreeLimits) {
+		taxFreeLimitIndex = len(s.TaxFreeLimits) - 1
+	}
+	taxFreeLimit := s.TaxFreeLimits[taxFreeLimitIndex]
+
+	// Calculate taxable gains (after exemption)
+	taxableGains := math.Max(0, result.CapitalGains-taxFreeLimit)
+
+	// Calculate tax on gains
+	result.Tax = taxableGains * (s.CapitalGainsTax / 100)
+
+	// Depreciation taken on an investment property is recaptured
+	if s.InvestmentProperty && !s.ownsRebuyHome(months) {
+		depreciatedMonths := min(months, DepreciationMonths)
+		result.Tax += s.MonthlyDepreciation() * float64(depreciatedMonths) * DepreciationRecaptureRate / 100
+	}
+
+	// Calculate net proceeds
+	result.NetProceeds = result.SalePrice - result.SellingCosts - result.LoanPayoff - result.Tax
+
+	return result
+}
+
+// RentingNetWorthAt calculates the renter's net worth after the given number of months
+// Uses month-by-month calculation: investment grows from downpayment + monthly savings
+func (s *Scenario) RentingNetWorthAt(months int) RentingResult {
+	var result RentingResult
+
+	// Investment starts with downpayment minus deposit, then each month's savings
+	// are added and grown; cumulative savings track the same flows without growth
+	index := s.prefixIndex(months)
+	investmentValue := s.rentingInvestment[index]
+	result.CumulativeSavings = s.cumulativeSavings[index]
+
+	// Pay the lease-break penalty if leaving mid-lease
+	penalty := s.leaseBreakPenaltyAt(months)
+	investmentValue -= penalty
+	result.CumulativeSavings -= penalty
+
+	// Prepaid rent comes back as the final months' rent
+	investmentValue += s.UpfrontRent()
+	result.CumulativeSavings += s.UpfrontRent()
+
+	// Add back the recoverable part of the deposit; any growth on it counts as market return
+	recoverableDeposit := s.RentDeposit * RecoverableDepositFraction
+
+	result.NetWorth = investmentValue + s.recoverableDepositAt(months)
+	result.MarketReturn = result.NetWorth - result.CumulativeSavings - recoverableDeposit
+	return result
+}
+
+// recoverableDepositAt returns the part of the deposit the renter gets back after the given
+// number of months, grown at the investment return rate with DepositEarnsReturns
+func (s *Scenario) recoverableDepositAt(months int) float64 {
+	deposit := s.RentDeposit * RecoverableDepositFraction
+	if s.DepositEarnsReturns {
+		for i := 0; i < months; i++ {
+			deposit *= 1 + s.monthlyInvestmentRate(i)
+		}
+	}
+	return deposit
+}
+
+// leaseBreakPenaltyAt returns the lease-break penalty owed when the renter leaves after
+// the given number of months, which is only the case mid-lease
+func (s *Scenario) leaseBreakPenaltyAt(months int) float64 {
+	if s.LeaseBreakPenalty > 0 && months%LeaseMonths != 0 {
+		return s.LeaseBreakPenalty
+	}
+	return 0
+}
+
+// ExpenditureAt calculates the total spent buying and renting after the given number of months
+func (s *Scenario) ExpenditureAt(months int) ExpenditureResult {
+	var result ExpenditureResult
+
+	// Total buying expenditure (downpayment + all monthly costs)
+	result.Buying = s.buyingExpenditure[s.prefixIndex(months)]
+
+	// Total renting expenditure (deposit + all monthly costs)
+	result.Renting = s.rentingExpenditure[s.prefixIndex(months)] + s.leaseBreakPenaltyAt(months)
+
+	result.Difference = result.Buying - result.Renting
+	return result
+}
+
+// SellNetWorthAt calculates net worth after the given months if selling now and investing the proceeds
+func (s *Scenario) SellNetWorthAt(months int) float64 {
+	// Calculate net proceeds from selling now
+	netProceeds := s.SaleProceedsNow().NetProceeds
+
+	if s.IncludeRenting {
+		// Start investment with net proceeds minus rental deposit, broker fee and prepaid rent
+		investmentValue := netProceeds - s.RentDeposit - s.BrokerFee - s.UpfrontRent()
+
+		// For each month: subtract rental costs, grow investment
+		for i := 0; i < months; i++ {
+			// Subtract renting costs
+			investmentValue -= s.monthlyRentingCosts[i]
+
+			// Apply monthly growth
+			investmentValue *= (1 + s.monthlyInvestmentRate(i))
+		}
+
+		// Pay the lease-break penalty if leaving mid-lease; prepaid rent comes back as the
+		// final months' rent
+		investmentValue -= s.leaseBreakPenaltyAt(months)
+		investmentValue += s.UpfrontRent()
+
+		// Add back the recoverable part of the deposit
+		return investmentValue + s.recoverableDepositAt(months)
+	}
+
+	// Just invest the proceeds without rental costs
+	investmentValue := netProceeds
+
+	// Simple monthly compounding
+	for i := 0; i < months; i++ {
+		investmentValue *= (1 + s.monthlyInvestmentRate(i))
+	}
+
+	return investmentValue
+}
+
+// SaleProceedsNow calculates the proceeds of selling at the current market value today
+func (s *Scenario) SaleProceedsNow() SaleResult {
+	var result SaleResult
+
+	result.SalePrice = s.CurrentMarketValue
+	agentFee := result.SalePrice * (s.AgentCommission / 100)
+	result.SellingCosts = agentFee + s.StagingCosts + s.saleTransferTax(result.SalePrice)
+	result.LoanPayoff = s.LoanAmount
+	// Capital gains with selling costs deducted
+	result.CapitalGains = result.SalePrice - s.PurchasePrice - result.SellingCosts
+	// Use first tax-free limit (selling now = year 0)
+	taxFreeLimit := s.TaxFreeLimits[0]
+	taxableGains := math.Max(0, result.CapitalGains-taxFreeLimit)
+	result.Tax = taxableGains * (s.CapitalGainsTax / 100)
+	result.NetProceeds = result.SalePrice - result.SellingCosts - result.LoanPayoff - result.Tax
+
+	return result
+}
+
+// KeepPositionAt returns the KEEP scenario's investment position after the given number of months
+func (s *Scenario) KeepPositionAt(months int) KeepPositionResult {
+	monthIndex := s.monthIndex(months)
+	return KeepPositionResult{
+		InvestmentValue: s.keepInvestmentValue[monthIndex],
+		RealCosts:       s.keepRealCosts[monthIndex],
+		NetPosition:     s.keepNetPosition[monthIndex],
+	}
+}
+
+// KeepNetWorthAt calculates net worth if keeping the asset and selling after the given number of months
+func (s *Scenario) KeepNetWorthAt(months int) float64 {
+	// Net proceeds from selling at this future point account for appreciation,
+	// selling costs, loan payoff, and capital gains tax
+	netProceeds := s.SaleProceedsAt(months).NetProceeds
+
+	return netProceeds + s.KeepPositionAt(months).NetPosition
+}
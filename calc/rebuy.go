@@ -0,0 +1,85 @@
+package calc
+
+import "math"
+
+// Sell and re-buy assumptions:
+//   - At the start of RebuyYear the home is sold at its appreciated value (see SaleProceedsAt)
+//     and a home costing RebuyPrice is bought the same month.
+//   - The net sale proceeds become the new downpayment. Proceeds beyond the new price come back
+//     as cash; a shortfall (or the whole price, without a loan term) is paid in cash.
+//   - The new loan has the same rate and term as the original loan; any recast must fall before it.
+//   - Recurring ownership costs scale by the new price over the sold home's value.
+//   - The new home appreciates at the rates for the years after the re-buy. Capital improvements,
+//     depreciation and the purchase transfer tax apply to the first home only.
+
+// hasRebuy reports whether the home is sold and another one bought during the projection
+func (s *Scenario) hasRebuy() bool {
+	return s.RebuyYear > 0 && s.RebuyPrice > 0
+}
+
+// rebuyMonth returns the number of months before the re-buy
+func (s *Scenario) rebuyMonth() int {
+	return s.RebuyYear * 12
+}
+
+// ownsRebuyHome reports whether the home held after the given months is the re-bought one
+func (s *Scenario) ownsRebuyHome(months int) bool {
+	return s.hasRebuy() && months > s.rebuyMonth()
+}
+
+// homeValueAt returns the value after the given months of whichever home is held then
+func (s *Scenario) homeValueAt(startingPrice float64, months int) float64 {
+	if s.ownsRebuyHome(months) {
+		return s.rebuyValue(months)
+	}
+	return s.appreciatedValue(startingPrice, months)
+}
+
+// rebuyValue appreciates RebuyPrice from the re-buy to the given months
+func (s *Scenario) rebuyValue(months int) float64 {
+	value := s.RebuyPrice
+	years := months / 12
+	remainingMonths := months % 12
+
+	for year := s.RebuyYear; year < years; year++ {
+		if s.AppreciationInDollars {
+			value += s.appreciationRate(year)
+		} else {
+			value *= (1 + s.appreciationRate(year)/100)
+		}
+	}
+	if remainingMonths > 0 {
+		if s.AppreciationInDollars {
+			value += s.appreciationRate(years) * float64(remainingMonths) / 12.0
+		} else {
+			value *= math.Pow(1+s.appreciationRate(years)/100, float64(remainingMonths)/12.0)
+		}
+	}
+	return value
+}
+
+// rebuyCostScale returns the factor recurring ownership costs change by at the re-buy
+func (s *Scenario) rebuyCostScale() float64 {
+	soldValue := s.appreciatedValue(s.PurchasePrice, s.rebuyMonth())
+	if soldValue <= 0 {
+		return 1
+	}
+	return s.RebuyPrice / soldValue
+}
+
+// startRebuy sells the home after the given months and buys the new one, returning the
+// new loan's balance and payment, and the cash paid (negative when cash comes back)
+func (s *Scenario) startRebuy(months int) (balance, payment, cashPaid float64) {
+	s.RebuyProceeds = s.SaleProceedsAt(months).NetProceeds
+
+	downpayment := math.Min(math.Max(s.RebuyProceeds, 0), s.RebuyPrice)
+	if s.LoanMonths <= 0 {
+		downpayment = s.RebuyPrice
+	}
+	s.RebuyLoanAmount = s.RebuyPrice - downpayment
+	if s.RebuyLoanAmount > 0 {
+		s.RebuyPayment = s.loanPayment(s.RebuyLoanAmount, s.AnnualRate, 0, s.LoanMonths)
+	}
+
+	return s.RebuyLoanAmount, s.RebuyPayment, downpayment - s.RebuyProceeds
+}
@@ -0,0 +1,82 @@
+package calc
+
+// CashOnCashYear is one year of an investment property's rental cash flow
+type CashOnCashYear struct {
+	Year         int     // 1-based
+	Income       float64 // Rent collected
+	CashFlow     float64 // Income less the loan payment and recurring costs
+	CashInvested float64 // Upfront cash (see UpfrontCash) and capital outlays to date
+	Return       float64 // CashFlow / CashInvested (%)
+}
+
+// HasRentalIncome reports whether the home is rented out as an investment property
+func (s *Scenario) HasRentalIncome() bool {
+	return s.InvestmentProperty && s.RentalIncome > 0
+}
+
+// CashOnCashByYear returns the cash-on-cash return of each full year the investment property
+// is rented out, up to the re-buy. Cash invested counts the money put in up front plus the
+// improvements and recast lump sum (with any early repayment charge) paid by the end of the year.
+func (s *Scenario) CashOnCashByYear() []CashOnCashYear {
+	if !s.HasRentalIncome() {
+		return nil
+	}
+	years := s.Months() / 12
+	if s.hasRebuy() {
+		years = min(years, s.RebuyYear)
+	}
+
+	var result []CashOnCashYear
+	for year := 0; year < years; year++ {
+		entry := CashOnCashYear{Year: year + 1}
+		for i := year * 12; i < (year+1)*12; i++ {
+			entry.Income += s.monthlyRentalIncome[i]
+			entry.CashFlow += s.rentalCashFlow[i]
+		}
+
+		end := (year + 1) * 12
+		entry.CashInvested = s.UpfrontCash() + s.ImprovementsBefore(end)
+		if s.hasRecast() && s.RecastMonth <= end {
+			entry.CashInvested += s.recastLumpSum + s.OverpaymentPenalty
+		}
+		if entry.CashInvested > 0 {
+			entry.Return = entry.CashFlow / entry.CashInvested * 100
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// InvestmentMetrics holds an investment property's first-year screening metrics
+type InvestmentMetrics struct {
+	AnnualRent          float64 // Rent collected in the first year
+	OperatingExpenses   float64 // First-year ownership costs, excluding the loan
+	NetOperatingIncome  float64 // AnnualRent - OperatingExpenses
+	GrossRentMultiplier float64 // PurchasePrice / AnnualRent (0 without rent)
+	CapRate             float64 // NetOperatingIncome / PurchasePrice (%) (0 without a price)
+}
+
+// InvestmentMetrics returns the gross rent multiplier and cap rate of the investment property
+// over its first year. Operating expenses are the recurring ownership costs without the loan,
+// so the metrics don't depend on how the purchase is financed.
+func (s *Scenario) InvestmentMetrics() InvestmentMetrics {
+	var result InvestmentMetrics
+	if !s.InvestmentProperty {
+		return result
+	}
+
+	for i := 0; i < min(12, len(s.monthlyRentalIncome)); i++ {
+		result.AnnualRent += s.monthlyRentalIncome[i]
+	}
+	costs := s.KeepExpensesAt(12)
+	result.OperatingExpenses = costs.Insurance + costs.OtherCosts
+	result.NetOperatingIncome = result.AnnualRent - result.OperatingExpenses
+
+	if result.AnnualRent > 0 {
+		result.GrossRentMultiplier = s.PurchasePrice / result.AnnualRent
+	}
+	if s.PurchasePrice > 0 {
+		result.CapRate = result.NetOperatingIncome / s.PurchasePrice * 100
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package calc
+
+import "math"
+
+// Bounds of the monthly rate searched for an internal rate of return
+const (
+	minIRRMonthlyRate = -0.5
+	maxIRRMonthlyRate = 0.5
+)
+
+// buyingCashFlows returns the buyer's cash flow at the start of each month over the given
+// months: the upfront cash and monthly costs go out, and the net worth comes back at the end
+func (s *Scenario) buyingCashFlows(months int) []float64 {
+	flows := make([]float64, months+1)
+	flows[0] = -s.buyingExpenditure[0]
+	for i := 0; i < months; i++ {
+		flows[i] -= s.monthlyBuyingCosts[i]
+	}
+	flows[months] += s.NetWorthAt(months).NetWorth
+	return flows
+}
+
+// rentingCashFlows returns the renter's cash flow at the start of each month over the given
+// months: the deposit, broker fee, prepaid rent and monthly costs go out, and the recoverable deposit and
+// prepaid rent come back at the end
+func (s *Scenario) rentingCashFlows(months int) []float64 {
+	flows := make([]float64, months+1)
+	flows[0] = -s.RentDeposit - s.BrokerFee - s.UpfrontRent()
+	for i := 0; i < months; i++ {
+		flows[i] -= s.monthlyRentingCosts[i]
+	}
+	flows[months] += s.recoverableDepositAt(months) + s.UpfrontRent() - s.leaseBreakPenaltyAt(months)
+	return flows
+}
+
+// presentValue discounts the monthly cash flows at the given monthly rate
+func presentValue(flows []float64, monthlyRate float64) float64 {
+	total := 0.0
+	for t, flow := range flows {
+		total += flow / math.Pow(1+monthlyRate, float64(t))
+	}
+	return total
+}
+
+// hasSignChange reports whether the cash flows go both in and out, which an IRR requires
+func hasSignChange(flows []float64) bool {
+	hasIn, hasOut := false, false
+	for _, flow := range flows {
+		if flow > 0 {
+			hasIn = true
+		} else if flow < 0 {
+			hasOut = true
+		}
+	}
+	return hasIn && hasOut
+}
+
+// NPVResult is the net present value of each side's cash flows over a horizon
+type NPVResult struct {
+	Buying     float64 // Upfront cash and buying costs out, net worth in at the end
+	Renting    float64 // Deposit and renting costs out, recoverable deposit in at the end
+	Difference float64 // Renting - Buying
+}
+
+// NPVAt discounts both sides' monthly cash flows over the given months at the annual discount rate (%)
+func (s *Scenario) NPVAt(months int, annualDiscountRate float64) NPVResult {
+	var result NPVResult
+	months = min(max(months, 0), s.Months())
+	monthlyRate := annualDiscountRate / 100 / 12
+
+	result.Buying = presentValue(s.buyingCashFlows(months), monthlyRate)
+	result.Renting = presentValue(s.rentingCashFlows(months), monthlyRate)
+	result.Difference = result.Renting - result.Buying
+	return result
+}
+
+// BuyingIRR returns the annualized internal rate of return (%) of buying over the given
+// months, treating it as a cash-flow stream. It reports false if there is no IRR.
+func (s *Scenario) BuyingIRR(months int) (float64, bool) {
+	if months <= 0 || months > s.Months() {
+		return 0, false
+	}
+	flows := s.buyingCashFlows(months)
+	if !hasSignChange(flows) {
+		return 0, false
+	}
+
+	monthlyRate, ok := bisect(func(rate float64) float64 {
+		return presentValue(flows, rate)
+	}, minIRRMonthlyRate, maxIRRMonthlyRate)
+	if !ok {
+		return 0, false
+	}
+	return (math.Pow(1+monthlyRate, 12) - 1) * 100, true
+}
@@ -0,0 +1,810 @@
+// Package calc implements the projection math behind the BUY vs RENT and SELL vs KEEP
+// comparisons: loan amortization, monthly cost schedules, appreciation, sale proceeds
+// and the net worth of each side over time.
+package calc
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultProjectionMonths is the minimum number of months a scenario projects (30 years)
+const DefaultProjectionMonths = 360
+
+// RecoverableDepositFraction is the share of the rental deposit returned at move-out
+const RecoverableDepositFraction = 0.75
+
+// DepreciationMonths is the straight-line depreciation period of residential rental property (27.5 years)
+const DepreciationMonths = 330
+
+// DepreciationRecaptureRate is the tax rate (%) on depreciation recaptured at sale
+const DepreciationRecaptureRate = 25.0
+
+// LeaseMonths is the length of a rental lease; leaving at any other point breaks the lease
+const LeaseMonths = 12
+
+// Inputs holds all parameters of a scenario
+type Inputs struct {
+	// Economic
+	InflationRates        []float64 // Annual inflation for recurring costs by year (%), last rate applies to all remaining years
+	InvestmentReturnRates []float64 // Annual return on invested savings by year (%), last rate applies to all remaining years
+	InvestmentFee         float64   // Annual fees / expense ratio (%) subtracted from the investment return
+
+	// Buying/Asset
+	PurchasePrice      float64 // Original purchase price (for capital gains)
+	CurrentMarketValue float64 // Current value (SELL vs KEEP only, 0 otherwise)
+	Downpayment        float64 // Cash put in (BUY vs RENT) or current equity (SELL vs KEEP)
+	LoanAmount         float64 // Loan principal (remaining balance for SELL vs KEEP)
+	AnnualRate         float64 // Loan interest rate (%)
+	LoanMonths         int     // Loan term (remaining term for SELL vs KEEP)
+	AnnualInsurance    float64 // Annual tax & insurance (insurance only when PropertyTaxRate is set)
+	PropertyTaxRate    float64 // Property tax as % of the home's value per year (0 = included in AnnualInsurance)
+	// CapPropertyTax grows AnnualInsurance (the property tax line) at TaxReassessmentCap (%)
+	// per year instead of inflation, as with California's Prop 13. With PropertyTaxRate, it
+	// limits how fast the value-based tax can grow instead.
+	CapPropertyTax      bool
+	TaxReassessmentCap  float64
+	AnnualTaxes         float64   // Other annual ownership costs
+	MonthlyExpenses     float64   // Monthly ownership expenses (negative for income)
+	ReplacementReserve  float64   // Monthly sinking fund for roofs, HVAC, appliances (inflated)
+	BuyUtilities        float64   // Monthly utilities when owning (inflated)
+	AppreciationRates   []float64 // Annual appreciation by year, last rate applies to all remaining years
+	CapitalImprovements []float64 // Improvements spent at the start of each year (no extension beyond the list)
+	RecastMonth         int       // Month after whose payment the lump sum is paid and the loan recast (0 = no recast)
+	RecastAmount        float64   // Lump-sum principal prepayment at the recast
+	LoanStart           time.Time // Calendar month of the first loan payment (zero = not set)
+	// OverpaymentCapPct caps the extra principal applied in a loan year at this % of the
+	// balance at the start of the year (0 = no cap). The part of the recast lump sum above the
+	// cap isn't applied, and pays an early repayment charge of OverpaymentPenaltyPct (%).
+	OverpaymentCapPct     float64
+	OverpaymentPenaltyPct float64
+	// OffsetSavings are savings linked to the loan (a UK offset mortgage): interest accrues on
+	// the balance less the savings while the payment stays the same, so the loan is paid off
+	// early. The savings themselves stay outside both sides' net worth.
+	OffsetSavings float64
+	// ActualDayCount accrues loan interest over the actual days of each month / 365 instead of
+	// 30/360 (a twelfth of the annual rate). It needs LoanStart to know each month's length.
+	ActualDayCount bool
+	// PaymentsPerYear is the loan payment frequency (see PaymentFrequencies); 0 means monthly.
+	// Interest accrues per payment period, and the payments are bucketed into calendar months.
+	PaymentsPerYear int
+	// SemiAnnualCompounding compounds AnnualRate twice a year (Canadian fixed mortgages)
+	// instead of once per payment (see PeriodRate)
+	SemiAnnualCompounding bool
+	// FixedMonths is the fixed-rate period of the first loan, after which it reverts to SVRRate
+	// (%), a UK standard variable rate, with the payment recomputed over the remaining term
+	// (0 = the rate stays fixed for the whole term)
+	FixedMonths int
+	SVRRate     float64
+	// Leasehold charges (annual, as on UK leasehold flats). Ground rent grows at
+	// GroundRentIncrease (%) per year; the service charge grows with inflation, or at
+	// ServiceChargeIncrease (%) with ServiceChargeOwnRate.
+	GroundRent            float64
+	GroundRentIncrease    float64
+	ServiceCharge         float64
+	ServiceChargeOwnRate  bool
+	ServiceChargeIncrease float64
+	// CouncilTax is the annual UK council tax paid by the owner; it grows with inflation, or at
+	// CouncilTaxIncrease (%) with CouncilTaxOwnRate
+	CouncilTax         float64
+	CouncilTaxOwnRate  bool
+	CouncilTaxIncrease float64
+	// AppreciationInDollars treats AppreciationRates as fixed dollar amounts added per year instead of percentages
+	AppreciationInDollars bool
+
+	// Renting
+	RentDeposit       float64
+	MonthlyRent       float64
+	AnnualRentCosts   float64
+	OtherAnnualCosts  float64
+	RentersInsurance  float64 // Annual renters insurance
+	RentUtilities     float64 // Monthly utilities when renting (inflated)
+	MoveCost          float64 // Cost of each move (inflated like other recurring costs)
+	MoveEveryMonths   int     // Months between moves (0 = never move)
+	LeaseBreakPenalty float64 // Paid once when the horizon falls mid-lease
+	RentFreeMonths    int     // Months of free rent at the start of the lease
+	RentUpfrontMonths int     // Months of rent prepaid at move-in (e.g., 2 for first and last month)
+	BrokerFee         float64 // Broker fee paid once at move-in (not recoverable)
+	IncludeRenting    bool    // SELL vs KEEP: selling means renting
+	// DepositEarnsReturns grows the recoverable deposit at the investment return rate while
+	// the landlord holds it, instead of returning it without growth
+	DepositEarnsReturns bool
+	// CapRentIncrease limits the yearly growth of the rent itself to RentIncreaseCap (%) when
+	// inflation is higher, as under rent control. Other renting costs still grow with inflation.
+	CapRentIncrease bool
+	RentIncreaseCap float64
+
+	// Investment property: depreciation of the purchase price shields income at IncomeTaxRate (%)
+	// and is recaptured at sale
+	InvestmentProperty bool
+	IncomeTaxRate      float64
+	// RentalIncome is the monthly rent collected on the investment property (inflated yearly).
+	// It offsets the buying costs until a re-buy, before income tax.
+	RentalIncome float64
+
+	// GrossMonthlyIncome is the buyer's pre-tax monthly income, for the affordability check (0 = skipped)
+	GrossMonthlyIncome float64
+
+	// Selling
+	IncludeSelling  bool    // BUY vs RENT: buying net worth is net of selling costs
+	AgentCommission float64 // % of sale price
+	StagingCosts    float64 // Fixed selling costs in today's dollars
+	// TransferTaxPct (% of price) is paid at purchase in BUY vs RENT, and at sale too with TransferTaxOnSale
+	TransferTaxPct    float64
+	TransferTaxOnSale bool
+	StampDuty         float64   // Stamp duty paid at purchase in BUY vs RENT (see StampDuty)
+	TaxFreeLimits     []float64 // Tax-free capital gains by year, last limit applies to all remaining years
+	CapitalGainsTax   float64   // %
+	// SellerConcessions are credits from the seller at closing (BUY vs RENT). They lower the
+	// buyer's cash at purchase, and the cost basis for capital gains, but not the loan.
+	SellerConcessions float64
+	// GiftFunds are gifted toward the downpayment (BUY vs RENT): the buyer supplies that much
+	// less cash, while the equity stays the same. The renter has no gift to invest.
+	GiftFunds float64
+
+	// Sell and re-buy (BUY vs RENT): after RebuyYear years, sell and buy a home costing RebuyPrice
+	// (see rebuy.go for the assumptions)
+	RebuyYear  int
+	RebuyPrice float64
+
+	// SquareFeet is the optional living area, used only for per-square-foot metrics
+	SquareFeet float64
+
+	// ProjectionMonths extends the projection beyond DefaultProjectionMonths and the loan term
+	ProjectionMonths int
+}
+
+// Scenario holds the inputs along with the month-by-month cost and loan schedules derived from them
+type Scenario struct {
+	Inputs
+
+	MonthlyRate             float64 // Monthly loan interest rate
+	MonthlyLoanPayment      float64
+	RecastPayment           float64 // Monthly loan payment after the recast (0 if there is none)
+	SVRPayment              float64 // Monthly loan payment once the fixed period ends (0 if there is none)
+	OverpaymentAllowance    float64 // Extra principal the cap allows in the recast's loan year (with OverpaymentCapPct)
+	OverpaymentExcess       float64 // Part of the recast lump sum above the cap, which isn't applied
+	OverpaymentPenalty      float64 // Early repayment charge on the excess
+	RebuyProceeds           float64 // Net proceeds of the sale at the re-buy
+	RebuyLoanAmount         float64 // Loan taken for the re-bought home
+	RebuyPayment            float64 // Monthly payment of the new loan
+	TotalMonthlyBuyingCost  float64 // Loan payment plus recurring ownership costs in the first month
+	TotalMonthlyRentingCost float64 // Rent plus recurring renting costs in the first month
+
+	monthlyBuyingCosts      []float64
+	monthlyRentingCosts     []float64
+	propertyTaxes           []float64 // Yearly value-based property tax
+	remainingLoanBalance    []float64
+	cumulativePrincipalPaid []float64
+	cumulativeInterestPaid  []float64
+	monthlyRentalIncome     []float64 // Rent collected on an investment property
+	rentalCashFlow          []float64 // Rental income less the loan payment and recurring costs
+	recastLumpSum           float64   // Principal prepaid at the recast
+
+	// Running totals indexed by the number of months elapsed (index 0 = before the first month)
+	cumulativeBuyingCosts []float64 // Sum of monthly buying costs
+	buyingExpenditure     []float64 // Downpayment plus all monthly buying costs
+	rentingExpenditure    []float64 // Deposit plus all monthly renting costs
+	cumulativeSavings     []float64 // Downpayment - deposit plus monthly savings (buying - renting), without growth
+	rentingInvestment     []float64 // Investment value of the renter's savings, compounded monthly
+
+	// KEEP scenario investment tracking
+	keepInvestmentValue []float64 // Investment value at each month
+	keepRealCosts       []float64 // Cumulative real out-of-pocket costs at each month
+	keepNetPosition     []float64 // Net position (investment - real costs) at each month
+
+	// KEEP scenario expenses per year
+	keepYearlyExpenses []KeepExpensesResult
+}
+
+// NewScenario derives the loan payment and monthly schedules for the given inputs
+func NewScenario(inputs Inputs) *Scenario {
+	s := &Scenario{Inputs: inputs}
+
+	if len(s.InflationRates) == 0 {
+		s.InflationRates = []float64{0}
+	}
+	if len(s.InvestmentReturnRates) == 0 {
+		s.InvestmentReturnRates = []float64{0}
+	}
+	if len(s.AppreciationRates) == 0 {
+		s.AppreciationRates = []float64{0}
+	}
+	if len(s.TaxFreeLimits) == 0 {
+		s.TaxFreeLimits = []float64{0}
+	}
+
+	if s.PaymentsPerYear == 0 {
+		s.PaymentsPerYear = 12
+	}
+
+	if s.LoanAmount > 0 {
+		s.MonthlyRate = PeriodRate(s.AnnualRate, 12, s.SemiAnnualCompounding)
+		s.MonthlyLoanPayment = s.loanPayment(s.LoanAmount, s.AnnualRate, 0, s.LoanMonths)
+	} else if s.hasRebuy() {
+		s.MonthlyRate = PeriodRate(s.AnnualRate, 12, s.SemiAnnualCompounding)
+	}
+
+	s.populatePropertyTaxes(max(DefaultProjectionMonths, s.LoanMonths, s.ProjectionMonths)/12 + 1)
+
+	monthlyRecurringExpenses := MonthlyRecurringExpenses(s.AnnualInsurance, s.AnnualTaxes, s.MonthlyExpenses+s.ReplacementReserve+s.BuyUtilities)
+	s.TotalMonthlyBuyingCost = s.MonthlyLoanPayment + monthlyRecurringExpenses + s.LeaseholdChargesAt(0) + s.CouncilTaxAt(0)
+	if s.PropertyTaxRate > 0 {
+		s.TotalMonthlyBuyingCost += s.PropertyTaxAt(0) / 12
+	}
+	s.TotalMonthlyRentingCost = MonthlyRentingCost(s.MonthlyRent+s.RentUtilities, s.AnnualRentCosts, s.OtherAnnualCosts, s.RentersInsurance)
+
+	s.populate(max(DefaultProjectionMonths, s.LoanMonths, s.ProjectionMonths))
+	return s
+}
+
+// PurchaseTransferTax returns the transfer tax and stamp duty paid at purchase, which only
+// BUY vs RENT includes
+func (s *Scenario) PurchaseTransferTax() float64 {
+	if s.CurrentMarketValue > 0 {
+		return 0
+	}
+	return s.PurchasePrice*s.TransferTaxPct/100 + s.StampDuty
+}
+
+// UpfrontCash returns the buyer's own cash at purchase: the downpayment and purchase transfer
+// tax, less any seller concessions and gift funds
+func (s *Scenario) UpfrontCash() float64 {
+	return s.Downpayment + s.PurchaseTransferTax() - s.SellerConcessions - s.GiftFunds
+}
+
+// saleTransferTax returns the transfer tax owed when selling at the given price
+func (s *Scenario) saleTransferTax(salePrice float64) float64 {
+	if !s.TransferTaxOnSale {
+		return 0
+	}
+	return salePrice * s.TransferTaxPct / 100
+}
+
+// PriceToRentRatio returns the purchase price over a year of rent, or false if there is no rent
+func (s *Scenario) PriceToRentRatio() (float64, bool) {
+	if s.MonthlyRent <= 0 {
+		return 0, false
+	}
+	return s.PurchasePrice / (s.MonthlyRent * 12), true
+}
+
+// UpfrontRent returns the rent prepaid at move-in. The monthly schedule still charges every
+// month, so the prepayment comes back at the end, when it covers the final months' rent;
+// what it costs the renter is the investment growth lost while it's held.
+func (s *Scenario) UpfrontRent() float64 {
+	return float64(s.RentUpfrontMonths) * s.MonthlyRent
+}
+
+// PaymentFrequencies maps each supported loan payment frequency to its payments per year
+var PaymentFrequencies = map[string]int{
+	"monthly":     12,
+	"semimonthly": 24,
+	"biweekly":    26,
+	"weekly":      52,
+}
+
+// loanPeriodsBefore returns the number of loan payments made in the first months of a loan.
+// Payments fall evenly through the year, so a month holds one, two, or (biweekly and
+// weekly) sometimes one more.
+func (s *Scenario) loanPeriodsBefore(months int) int {
+	return months * s.PaymentsPerYear / 12
+}
+
+// loanPayment returns the monthly payment that retires the principal at the annual rate (%)
+// between the given months of the loan. With other payment frequencies it's the per-period
+// payment averaged over a year.
+func (s *Scenario) loanPayment(principal, annualRate float64, elapsedMonths, loanMonths int) float64 {
+	if s.PaymentsPerYear == 12 {
+		return MonthlyPayment(principal, PeriodRate(annualRate, 12, s.SemiAnnualCompounding), loanMonths-elapsedMonths)
+	}
+	perYear := float64(s.PaymentsPerYear)
+	periods := s.loanPeriodsBefore(loanMonths) - s.loanPeriodsBefore(elapsedMonths)
+	return MonthlyPayment(principal, PeriodRate(annualRate, s.PaymentsPerYear, s.SemiAnnualCompounding), periods) * perYear / 12
+}
+
+// periodicPayments accrues interest at the annual rate (%) and applies each payment falling in
+// the given 0-based month of the loan, returning the total paid and the interest in it
+func (s *Scenario) periodicPayments(month int, balance, monthlyPayment, annualRate float64) (paid, interest float64) {
+	perYear := float64(s.PaymentsPerYear)
+	payment := monthlyPayment * 12 / perYear
+	periodRate := PeriodRate(annualRate, s.PaymentsPerYear, s.SemiAnnualCompounding)
+	for range s.loanPeriodsBefore(month+1) - s.loanPeriodsBefore(month) {
+		periodInterest := s.interestBearing(balance) * periodRate
+		balance -= payment - periodInterest
+		paid += payment
+		interest += periodInterest
+	}
+	return paid, interest
+}
+
+// interestBearing returns the part of the loan balance that accrues interest: all of it,
+// less any offset savings
+func (s *Scenario) interestBearing(balance float64) float64 {
+	return max(0, balance-s.OffsetSavings)
+}
+
+// Months returns the number of months covered by the scenario's schedules
+func (s *Scenario) Months() int {
+	return len(s.monthlyBuyingCosts)
+}
+
+// MonthlyRecurringExpenses returns the monthly ownership costs excluding the loan payment
+func MonthlyRecurringExpenses(annualInsurance, annualTaxes, monthlyExpenses float64) float64 {
+	totalAnnualExpenses := annualInsurance + annualTaxes
+	return (totalAnnualExpenses / 12) + monthlyExpenses
+}
+
+// MonthlyRentingCost returns the monthly rent plus the monthly share of annual renting costs
+func MonthlyRentingCost(monthlyRent, annualRentCosts, otherAnnualCosts, rentersInsurance float64) float64 {
+	monthlyRentingExpenses := (annualRentCosts / 12) + (otherAnnualCosts / 12) + (rentersInsurance / 12)
+	return monthlyRent + monthlyRentingExpenses
+}
+
+// monthIndex converts a period in months to an index into the monthly schedules,
+// clamped to the projected range
+func (s *Scenario) monthIndex(months int) int {
+	monthIndex := months - 1
+	if monthIndex >= len(s.monthlyBuyingCosts) {
+		monthIndex = len(s.monthlyBuyingCosts) - 1
+	}
+	if monthIndex < 0 {
+		monthIndex = 0
+	}
+	return monthIndex
+}
+
+// MonthlyDepreciation returns the straight-line depreciation of an investment property per month
+func (s *Scenario) MonthlyDepreciation() float64 {
+	if !s.InvestmentProperty {
+		return 0
+	}
+	return s.PurchasePrice / DepreciationMonths
+}
+
+// hasSVR reports whether the first loan reverts to the SVR within its term (and before any re-buy)
+func (s *Scenario) hasSVR() bool {
+	if s.hasRebuy() && s.FixedMonths >= s.rebuyMonth() {
+		return false
+	}
+	return s.LoanAmount > 0 && s.FixedMonths > 0 && s.FixedMonths < s.LoanMonths
+}
+
+// hasRecast reports whether the loan is recast within its term (and before any re-buy)
+func (s *Scenario) hasRecast() bool {
+	if s.hasRebuy() && s.RecastMonth > s.rebuyMonth() {
+		return false
+	}
+	return s.RecastAmount > 0 && s.RecastMonth > 0 && s.RecastMonth < s.LoanMonths
+}
+
+// loanPaymentAt returns the loan payment due in the given 0-based month of the loan term
+func (s *Scenario) loanPaymentAt(month int) float64 {
+	if s.hasRebuy() && month >= s.rebuyMonth() {
+		return s.RebuyPayment
+	}
+	// The later of the recast and the end of the fixed period sets the payment
+	if s.hasRecast() && month >= s.RecastMonth && (!s.hasSVR() || s.RecastMonth > s.FixedMonths) {
+		return s.RecastPayment
+	}
+	if s.hasSVR() && month >= s.FixedMonths {
+		return s.SVRPayment
+	}
+	if s.hasRecast() && month >= s.RecastMonth {
+		return s.RecastPayment
+	}
+	return s.MonthlyLoanPayment
+}
+
+// prefixIndex converts a period in months to an index into the running totals,
+// clamped to the projected range
+func (s *Scenario) prefixIndex(months int) int {
+	if months > len(s.monthlyBuyingCosts) {
+		months = len(s.monthlyBuyingCosts)
+	}
+	if months < 0 {
+		months = 0
+	}
+	return months
+}
+
+// populate fills the monthly schedules for buying and renting
+func (s *Scenario) populate(maxMonths int) {
+	s.monthlyBuyingCosts = make([]float64, maxMonths)
+	s.monthlyRentingCosts = make([]float64, maxMonths)
+	s.remainingLoanBalance = make([]float64, maxMonths)
+	s.cumulativePrincipalPaid = make([]float64, maxMonths)
+	s.cumulativeInterestPaid = make([]float64, maxMonths)
+	s.monthlyRentalIncome = make([]float64, maxMonths)
+	s.rentalCashFlow = make([]float64, maxMonths)
+
+	// Calculate monthly recurring expenses
+	monthlyRecurringExpenses := MonthlyRecurringExpenses(s.AnnualInsurance, s.AnnualTaxes, s.MonthlyExpenses+s.ReplacementReserve+s.BuyUtilities)
+
+	// Calculate current rental cost with annual increases
+	currentRentingCost := s.TotalMonthlyRentingCost
+
+	// Under rent control the rent grows separately, at most at the cap
+	currentRent := 0.0
+	if s.CapRentIncrease {
+		currentRent = s.MonthlyRent
+		currentRentingCost -= s.MonthlyRent
+	}
+
+	// Track current recurring expenses (will increase with inflation)
+	currentRecurringExpenses := monthlyRecurringExpenses
+
+	// With a reassessment cap, the property tax line grows separately at the capped rate
+	currentPropertyTax := 0.0
+	if s.capsTaxLine() {
+		currentPropertyTax = s.AnnualInsurance / 12
+		currentRecurringExpenses = MonthlyRecurringExpenses(0, s.AnnualTaxes, s.MonthlyExpenses+s.ReplacementReserve+s.BuyUtilities)
+	}
+
+	// Track remaining loan balance
+	currentBalance := s.LoanAmount
+	totalPrincipalPaid := 0.0
+	totalInterestPaid := 0.0
+	loanPayment := s.MonthlyLoanPayment
+	loanEnd := s.LoanMonths
+	loanStart := 0 // Month the current loan's payments start in
+	loanRate := s.AnnualRate
+	rebuyCashPaid := 0.0
+	currentRentalIncome := 0.0
+	if s.InvestmentProperty {
+		currentRentalIncome = s.RentalIncome
+	}
+
+	for i := 0; i < maxMonths; i++ {
+		// Apply the previous year's inflation to all costs at the start of each year (except the first month)
+		if i > 0 && i%12 == 0 {
+			inflationRate := s.inflationRate(i/12 - 1)
+			currentRentingCost *= (1 + inflationRate/100)
+			currentRent *= (1 + s.rentIncreaseRate(i/12-1)/100)
+			currentRecurringExpenses *= (1 + inflationRate/100)
+			currentPropertyTax *= (1 + s.TaxReassessmentCap/100)
+			currentRentalIncome *= (1 + inflationRate/100)
+		}
+
+		// Sell and re-buy: start the new loan and rescale ownership costs to the new home
+		if s.hasRebuy() && i == s.rebuyMonth() {
+			currentBalance, loanPayment, rebuyCashPaid = s.startRebuy(i)
+			loanEnd = i + s.LoanMonths
+			loanStart = i
+			loanRate = s.AnnualRate
+			currentRecurringExpenses *= s.rebuyCostScale()
+			currentPropertyTax *= s.rebuyCostScale()
+		}
+
+		// Set renting cost for this month, including moving costs in the months a move occurs.
+		// Free months skip the cost but rent still inflates from its annualized level.
+		s.monthlyRentingCosts[i] = currentRentingCost + currentRent
+		if i < s.RentFreeMonths {
+			s.monthlyRentingCosts[i] = 0
+		}
+		if s.MoveCost > 0 && s.MoveEveryMonths > 0 && i > 0 && i%s.MoveEveryMonths == 0 {
+			s.monthlyRentingCosts[i] += s.MoveCost * s.inflationFactor(i/12)
+		}
+
+		// Buying cost: loan payment stops after loan duration, but recurring expenses continue
+		if i < loanEnd {
+			// Calculate interest for this month, or for each payment period in it
+			// Once the fixed period ends, the rate reverts to the SVR over the remaining term
+			if s.hasSVR() && i == s.FixedMonths {
+				loanRate = s.SVRRate
+				s.SVRPayment = s.loanPayment(currentBalance, loanRate, s.FixedMonths, s.LoanMonths)
+				loanPayment = s.SVRPayment
+			}
+
+			paid, interestPayment := loanPayment, s.interestBearing(currentBalance)*s.monthlyInterestRate(i, loanRate)
+			if s.PaymentsPerYear != 12 {
+				paid, interestPayment = s.periodicPayments(i-loanStart, currentBalance, loanPayment, loanRate)
+			}
+			s.monthlyBuyingCosts[i] = paid + currentRecurringExpenses + currentPropertyTax
+
+			// Principal payment is the remainder
+			principalPayment := paid - interestPayment
+			// An offset loan is paid off early: the last payment clears the balance, then payments stop
+			if s.OffsetSavings > 0 && principalPayment > currentBalance {
+				s.monthlyBuyingCosts[i] -= principalPayment - currentBalance
+				principalPayment = currentBalance
+			}
+			// With actual/365 accrual the fixed payment doesn't retire the loan exactly (nor, by
+			// rounding, do per-period payments), so the final payment settles whatever is left
+			if (s.ActualDayCount || s.PaymentsPerYear != 12) && i == loanEnd-1 {
+				s.monthlyBuyingCosts[i] += currentBalance - principalPayment
+				principalPayment = currentBalance
+			}
+			// Reduce the balance
+			currentBalance -= principalPayment
+
+			// Track cumulative amounts
+			totalPrincipalPaid += principalPayment
+			totalInterestPaid += interestPayment
+
+			// Recast: pay down principal with the lump sum, then lower the payment
+			// over the remaining term so the loan still ends on schedule
+			if s.hasRecast() && i == s.RecastMonth-1 {
+				lumpSum := math.Min(s.RecastAmount, currentBalance)
+
+				// The overpayment cap limits the extra principal applied in the loan year;
+				// the excess isn't applied and pays an early repayment charge
+				if s.OverpaymentCapPct > 0 {
+					yearStartBalance := s.LoanAmount
+					if yearStart := i - (i-loanStart)%12; yearStart > 0 {
+						yearStartBalance = s.remainingLoanBalance[yearStart-1]
+					}
+					s.OverpaymentAllowance = yearStartBalance * s.OverpaymentCapPct / 100
+					s.OverpaymentExcess = max(0, lumpSum-s.OverpaymentAllowance)
+					s.OverpaymentPenalty = s.OverpaymentExcess * s.OverpaymentPenaltyPct / 100
+					s.monthlyBuyingCosts[i] += s.OverpaymentPenalty
+					lumpSum -= s.OverpaymentExcess
+				}
+
+				currentBalance -= lumpSum
+				totalPrincipalPaid += lumpSum
+				s.monthlyBuyingCosts[i] += lumpSum
+				s.recastLumpSum = lumpSum
+
+				s.RecastPayment = s.loanPayment(currentBalance, loanRate, s.RecastMonth, s.LoanMonths)
+				loanPayment = s.RecastPayment
+			}
+
+			// Store remaining balance after this payment
+			s.remainingLoanBalance[i] = currentBalance
+			s.cumulativePrincipalPaid[i] = totalPrincipalPaid
+			s.cumulativeInterestPaid[i] = totalInterestPaid
+		} else {
+			// After loan is paid off, only recurring expenses remain
+			s.monthlyBuyingCosts[i] = currentRecurringExpenses + currentPropertyTax
+			s.remainingLoanBalance[i] = 0
+			s.cumulativePrincipalPaid[i] = totalPrincipalPaid
+			s.cumulativeInterestPaid[i] = totalInterestPaid
+		}
+
+		// Capital improvements are paid at the start of the year they're made
+		if i%12 == 0 {
+			s.monthlyBuyingCosts[i] += s.improvementAt(i / 12)
+		}
+
+		// Depreciation deductions lower the owner's taxes while the property is depreciating
+		if s.InvestmentProperty && i < DepreciationMonths && !s.ownsRebuyHome(i+1) {
+			s.monthlyBuyingCosts[i] -= s.MonthlyDepreciation() * s.IncomeTaxRate / 100
+		}
+
+		// Cash paid toward the new home at the re-buy beyond the sale proceeds
+		if s.hasRebuy() && i == s.rebuyMonth() {
+			s.monthlyBuyingCosts[i] += rebuyCashPaid
+		}
+
+		// Value-based property tax is reassessed each year
+		if s.PropertyTaxRate > 0 {
+			s.monthlyBuyingCosts[i] += s.PropertyTaxAt(i/12) / 12
+		}
+
+		// Leasehold charges escalate on their own schedules
+		if s.GroundRent != 0 || s.ServiceCharge != 0 {
+			leasehold := s.LeaseholdChargesAt(i / 12)
+			if s.ownsRebuyHome(i + 1) {
+				leasehold *= s.rebuyCostScale()
+			}
+			s.monthlyBuyingCosts[i] += leasehold
+		}
+
+		// Council tax escalates on its own schedule too
+		if s.CouncilTax != 0 {
+			councilTax := s.CouncilTaxAt(i / 12)
+			if s.ownsRebuyHome(i + 1) {
+				councilTax *= s.rebuyCostScale()
+			}
+			s.monthlyBuyingCosts[i] += councilTax
+		}
+
+		// Rent collected on the investment property offsets the costs until the re-buy. Its cash
+		// flow leaves out capital outlays (improvements, recast, re-buy) and the tax shield.
+		if currentRentalIncome > 0 && !s.ownsRebuyHome(i+1) {
+			operatingCost := s.monthlyBuyingCosts[i]
+			if i%12 == 0 {
+				operatingCost -= s.improvementAt(i / 12)
+			}
+			if s.hasRecast() && i == s.RecastMonth-1 {
+				operatingCost -= s.recastLumpSum + s.OverpaymentPenalty
+			}
+			if i < DepreciationMonths {
+				operatingCost += s.MonthlyDepreciation() * s.IncomeTaxRate / 100
+			}
+			s.monthlyRentalIncome[i] = currentRentalIncome
+			s.rentalCashFlow[i] = currentRentalIncome - operatingCost
+			s.monthlyBuyingCosts[i] -= currentRentalIncome
+		}
+	}
+
+	// Calculate running totals so lookups by period don't re-sum the schedules
+	s.accumulate(maxMonths)
+
+	// Calculate KEEP investment tracking and yearly expenses
+	s.trackKeepInvestment(maxMonths)
+	s.calculateKeepYearlyExpenses(maxMonths)
+}
+
+// populatePropertyTaxes computes the yearly property tax from the home's value at the start
+// of each year, limited by the reassessment cap when one is set
+func (s *Scenario) populatePropertyTaxes(numYears int) {
+	s.propertyTaxes = make([]float64, numYears)
+	if s.PropertyTaxRate <= 0 {
+		return
+	}
+
+	startingPrice := s.PurchasePrice
+	if s.CurrentMarketValue > 0 {
+		startingPrice = s.CurrentMarketValue
+	}
+
+	for year := range s.propertyTaxes {
+		// The re-bought home is assessed at its purchase price, resetting the cap
+		tax := s.homeValueAt(startingPrice, year*12) * s.PropertyTaxRate / 100
+		if s.hasRebuy() && year == s.RebuyYear {
+			tax = s.RebuyPrice * s.PropertyTaxRate / 100
+		} else if s.CapPropertyTax && year > 0 {
+			tax = math.Min(tax, s.propertyTaxes[year-1]*(1+s.TaxReassessmentCap/100))
+		}
+		s.propertyTaxes[year] = tax
+	}
+}
+
+// capsTaxLine reports whether AnnualInsurance is the capped property tax line, which
+// is not the case once the tax is value-based
+func (s *Scenario) capsTaxLine() bool {
+	return s.CapPropertyTax && s.PropertyTaxRate <= 0
+}
+
+// PropertyTaxAt returns the value-based property tax for the given 0-based year
+func (s *Scenario) PropertyTaxAt(year int) float64 {
+	if year >= len(s.propertyTaxes) {
+		year = len(s.propertyTaxes) - 1
+	}
+	return s.propertyTaxes[year]
+}
+
+// accumulate populates the running totals, adding month by month in the same order
+// as a direct summation so the results are identical
+func (s *Scenario) accumulate(maxMonths int) {
+	s.cumulativeBuyingCosts = make([]float64, maxMonths+1)
+	s.buyingExpenditure = make([]float64, maxMonths+1)
+	s.rentingExpenditure = make([]float64, maxMonths+1)
+	s.cumulativeSavings = make([]float64, maxMonths+1)
+	s.rentingInvestment = make([]float64, maxMonths+1)
+
+	// The renter invests the buyer's upfront cash, including any transfer tax
+	s.buyingExpenditure[0] = s.UpfrontCash()
+	s.rentingExpenditure[0] = s.RentDeposit + s.BrokerFee
+	// Prepaid rent leaves the renter's invested lump sum too (see UpfrontRent)
+	initial := s.UpfrontCash() - s.RentDeposit - s.BrokerFee - s.UpfrontRent()
+	s.cumulativeSavings[0] = initial
+	s.rentingInvestment[0] = initial
+
+	for i := 0; i < maxMonths; i++ {
+		s.cumulativeBuyingCosts[i+1] = s.cumulativeBuyingCosts[i] + s.monthlyBuyingCosts[i]
+		s.buyingExpenditure[i+1] = s.buyingExpenditure[i] + s.monthlyBuyingCosts[i]
+		s.rentingExpenditure[i+1] = s.rentingExpenditure[i] + s.monthlyRentingCosts[i]
+
+		// Monthly savings = buying cost - renting cost, invested and then grown
+		monthlySavings := s.monthlyBuyingCosts[i] - s.monthlyRentingCosts[i]
+		s.cumulativeSavings[i+1] = s.cumulativeSavings[i] + monthlySavings
+		s.rentingInvestment[i+1] = (s.rentingInvestment[i] + monthlySavings) * (1 + s.monthlyInvestmentRate(i))
+	}
+}
+
+// trackKeepInvestment populates the investment tracking schedules for the KEEP scenario
+func (s *Scenario) trackKeepInvestment(maxMonths int) {
+	s.keepInvestmentValue = make([]float64, maxMonths)
+	s.keepRealCosts = make([]float64, maxMonths)
+	s.keepNetPosition = make([]float64, maxMonths)
+
+	investmentValue := 0.0
+	totalRealCosts := 0.0
+
+	for i := 0; i < maxMonths; i++ {
+		monthlyCost := s.monthlyBuyingCosts[i]
+
+		if monthlyCost < 0 {
+			// Income: invest it
+			investmentValue += -monthlyCost
+		} else if monthlyCost > 0 {
+			// Expense: first use investment value, then real costs
+			if investmentValue >= monthlyCost {
+				investmentValue -= monthlyCost
+			} else {
+				// Use up all investment, remainder is real cost
+				deficit := monthlyCost - investmentValue
+				investmentValue = 0
+				totalRealCosts += deficit
+			}
+		}
+
+		// Compound whatever investment value remains
+		investmentValue *= (1 + s.monthlyInvestmentRate(i))
+
+		// Store values for this month
+		s.keepInvestmentValue[i] = investmentValue
+		s.keepRealCosts[i] = totalRealCosts
+		s.keepNetPosition[i] = investmentValue - totalRealCosts
+	}
+}
+
+// inflationRate returns the inflation rate (%) for the given 0-based year
+func (s *Scenario) inflationRate(year int) float64 {
+	if year >= len(s.InflationRates) {
+		year = len(s.InflationRates) - 1 // Use last rate for all future years
+	}
+	return s.InflationRates[year]
+}
+
+// rentIncreaseRate returns the rent increase (%) applied after the given 0-based year:
+// inflation, limited to the cap under rent control
+func (s *Scenario) rentIncreaseRate(year int) float64 {
+	if s.CapRentIncrease {
+		return math.Min(s.inflationRate(year), s.RentIncreaseCap)
+	}
+	return s.inflationRate(year)
+}
+
+// rentFactor returns the cumulative rent growth multiplier after the given number of years
+func (s *Scenario) rentFactor(years int) float64 {
+	if !s.CapRentIncrease {
+		return s.inflationFactor(years)
+	}
+	factor := 1.0
+	for year := 0; year < years; year++ {
+		factor *= 1 + s.rentIncreaseRate(year)/100
+	}
+	return factor
+}
+
+// LeaseholdChargesAt returns the monthly ground rent and service charge in the given 0-based year
+func (s *Scenario) LeaseholdChargesAt(year int) float64 {
+	groundRent := s.GroundRent * math.Pow(1+s.GroundRentIncrease/100, float64(year))
+	serviceCharge := s.ServiceCharge * s.inflationFactor(year)
+	if s.ServiceChargeOwnRate {
+		serviceCharge = s.ServiceCharge * math.Pow(1+s.ServiceChargeIncrease/100, float64(year))
+	}
+	return (groundRent + serviceCharge) / 12
+}
+
+// CouncilTaxAt returns the monthly council tax in the given 0-based year
+func (s *Scenario) CouncilTaxAt(year int) float64 {
+	if s.CouncilTaxOwnRate {
+		return s.CouncilTax * math.Pow(1+s.CouncilTaxIncrease/100, float64(year)) / 12
+	}
+	return s.CouncilTax * s.inflationFactor(year) / 12
+}
+
+// monthlyInvestmentRate returns the investment return for the given 0-based month,
+// using the annual rate of the year the month falls in
+func (s *Scenario) monthlyInvestmentRate(month int) float64 {
+	year := month / 12
+	if year >= len(s.InvestmentReturnRates) {
+		year = len(s.InvestmentReturnRates) - 1 // Use last rate for all future years
+	}
+	return (s.InvestmentReturnRates[year] - s.InvestmentFee) / 100 / 12
+}
+
+// NetInvestmentReturnRates returns the investment return schedule after fees
+func (s *Scenario) NetInvestmentReturnRates() []float64 {
+	rates := make([]float64, len(s.InvestmentReturnRates))
+	for i, rate := range s.InvestmentReturnRates {
+		rates[i] = rate - s.InvestmentFee
+	}
+	return rates
+}
+
+// inflationFactor returns the cumulative inflation multiplier after the given number of years
+func (s *Scenario) inflationFactor(years int) float64 {
+	factor := 1.0
+	year := 0
+	for ; year < years && year < len(s.InflationRates)-1; year++ {
+		factor *= 1 + s.InflationRates[year]/100
+	}
+	// The last rate applies to all remaining years
+	return factor * math.Pow(1+s.inflationRate(year)/100, float64(years-year))
+}
@@ -0,0 +1,79 @@
+package calc
+
+// Bounds of the constant rates (%/yr) searched for a breakeven
+const (
+	MinSolveAppreciation = -50.0
+	MaxSolveAppreciation = 50.0
+	MinSolveReturn       = -20.0
+	MaxSolveReturn       = 40.0
+)
+
+// bisect finds x in [lo, hi] where the increasing or decreasing function f crosses zero.
+// It reports false if f has the same sign at both ends.
+func bisect(f func(float64) float64, lo, hi float64) (float64, bool) {
+	fLo := f(lo)
+	fHi := f(hi)
+	if fLo == 0 {
+		return lo, true
+	}
+	if fHi == 0 {
+		return hi, true
+	}
+	if (fLo < 0) == (fHi < 0) {
+		return 0, false
+	}
+
+	for i := 0; i < 100 && hi-lo > 1e-9; i++ {
+		mid := (lo + hi) / 2
+		fMid := f(mid)
+		if fMid == 0 {
+			return mid, true
+		}
+		if (fMid < 0) == (fLo < 0) {
+			lo, fLo = mid, fMid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, true
+}
+
+// netWorthGap returns buying net worth minus renting net worth after the given months
+func netWorthGap(inputs Inputs, months int) float64 {
+	inputs.ProjectionMonths = max(inputs.ProjectionMonths, months)
+	s := NewScenario(inputs)
+	return s.NetWorthAt(months).NetWorth - s.RentingNetWorthAt(months).NetWorth
+}
+
+// BreakevenAppreciation finds the constant annual appreciation rate (%) at which buying and
+// renting net worth are equal after the given months, holding all other inputs fixed
+func BreakevenAppreciation(inputs Inputs, months int) (float64, bool) {
+	return bisect(func(rate float64) float64 {
+		inputs.AppreciationRates = []float64{rate}
+		inputs.AppreciationInDollars = false
+		return netWorthGap(inputs, months)
+	}, MinSolveAppreciation, MaxSolveAppreciation)
+}
+
+// BreakevenReturn finds the constant annual investment return (%) at which the renter's net
+// worth matches the buyer's after the given months, holding all other inputs fixed
+func BreakevenReturn(inputs Inputs, months int) (float64, bool) {
+	return bisect(func(rate float64) float64 {
+		inputs.InvestmentReturnRates = []float64{rate}
+		return netWorthGap(inputs, months)
+	}, MinSolveReturn, MaxSolveReturn)
+}
+
+// BreakevenRent finds the starting monthly rent at which renting and investing ties buying
+// after the given months, holding all other inputs fixed. Rents below it favor renting.
+func BreakevenRent(inputs Inputs, months int) (float64, bool) {
+	// Search up to well past both the current rent and the cost of owning
+	maxRent := 10 * (NewScenario(inputs).TotalMonthlyBuyingCost + inputs.MonthlyRent)
+	if maxRent <= 0 {
+		return 0, false
+	}
+	return bisect(func(rent float64) float64 {
+		inputs.MonthlyRent = rent
+		return netWorthGap(inputs, months)
+	}, 0, maxRent)
+}
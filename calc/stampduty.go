@@ -0,0 +1,29 @@
+package calc
+
+// StampDutyBand is one tier of a progressive stamp duty schedule (e.g., UK SDLT): Rate (%)
+// applies to the part of the price up to UpTo. The last band may have UpTo 0 for no limit.
+type StampDutyBand struct {
+	UpTo float64
+	Rate float64
+}
+
+// StampDuty returns the stamp duty on the given price, charging each band's rate on the
+// slice of the price that falls within it
+func StampDuty(price float64, bands []StampDutyBand) float64 {
+	duty := 0.0
+	lower := 0.0
+	for _, band := range bands {
+		upper := band.UpTo
+		if upper <= 0 || upper > price {
+			upper = price
+		}
+		if upper > lower {
+			duty += (upper - lower) * band.Rate / 100
+		}
+		if band.UpTo <= 0 || band.UpTo >= price {
+			break
+		}
+		lower = band.UpTo
+	}
+	return duty
+}
@@ -0,0 +1,73 @@
+//go:build chart
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+
+	"calculator/calc"
+)
+
+// writeChart renders buying vs renting net worth by month to a PNG, marking the crossover
+func writeChart(path string, scenario *calc.Scenario) error {
+	horizon := projectionHorizon()
+	buying := make(plotter.XYs, horizon)
+	renting := make(plotter.XYs, horizon)
+	var crossovers plotter.XYs
+
+	for month := 1; month <= horizon; month++ {
+		i := month - 1
+		buying[i].X = float64(month)
+		buying[i].Y = scenario.NetWorthAt(month).NetWorth
+		renting[i].X = float64(month)
+		renting[i].Y = scenario.RentingNetWorthAt(month).NetWorth
+
+		// Mark the months where the leading side changes
+		if i > 0 && (buying[i].Y >= renting[i].Y) != (buying[i-1].Y >= renting[i-1].Y) {
+			crossovers = append(crossovers, plotter.XY{X: buying[i].X, Y: buying[i].Y})
+		}
+	}
+
+	p := plot.New()
+	p.Title.Text = "Net Worth: Buy vs Rent"
+	p.X.Label.Text = "Month"
+	p.Y.Label.Text = "Net Worth"
+
+	buyingLine, err := plotter.NewLine(buying)
+	if err != nil {
+		return fmt.Errorf("failed to plot buying net worth: %v", err)
+	}
+	buyingLine.Color = color.RGBA{R: 31, G: 119, B: 180, A: 255}
+	rentingLine, err := plotter.NewLine(renting)
+	if err != nil {
+		return fmt.Errorf("failed to plot renting net worth: %v", err)
+	}
+	rentingLine.Color = color.RGBA{R: 214, G: 39, B: 40, A: 255}
+	rentingLine.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+
+	p.Add(plotter.NewGrid(), buyingLine, rentingLine)
+	p.Legend.Add("Buying", buyingLine)
+	p.Legend.Add("Renting", rentingLine)
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	if len(crossovers) > 0 {
+		points, err := plotter.NewScatter(crossovers)
+		if err != nil {
+			return fmt.Errorf("failed to plot crossover: %v", err)
+		}
+		points.Radius = vg.Points(4)
+		p.Add(points)
+		p.Legend.Add("Crossover", points)
+	}
+
+	if err := p.Save(8*vg.Inch, 5*vg.Inch, path); err != nil {
+		return fmt.Errorf("failed to save chart: %v", err)
+	}
+	return nil
+}
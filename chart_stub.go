@@ -0,0 +1,14 @@
+//go:build !chart
+
+package main
+
+import (
+	"fmt"
+
+	"calculator/calc"
+)
+
+// writeChart is unavailable unless built with the chart tag, which pulls in gonum/plot
+func writeChart(path string, scenario *calc.Scenario) error {
+	return fmt.Errorf("--chart needs a build with chart support (go build -tags chart)")
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"sort"
@@ -15,15 +16,31 @@ import (
 
 const defaultOutputFile = "market_data.json"
 
+// HTTP settings for market data requests, overridable with -http-timeout and -user-agent
+var (
+	httpTimeout = 30 * time.Second
+	userAgent   = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"
+)
+
+// newHTTPClient returns a client using the configured timeout and any HTTP_PROXY/HTTPS_PROXY
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   httpTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	}
+}
+
 // MarketData stores historical annual returns
 type MarketData struct {
 	LastUpdated      string             `json:"last_updated"`
-	VOO              map[string]float64 `json:"voo"`               // Year -> Annual return % (S&P 500)
-	QQQ              map[string]float64 `json:"qqq"`               // Year -> Annual return % (Nasdaq 100)
-	VTI              map[string]float64 `json:"vti"`               // Year -> Annual return % (Total Stock Market)
-	BND              map[string]float64 `json:"bnd"`               // Year -> Annual return % (Total Bond Market)
-	Inflation        map[string]float64 `json:"inflation"`         // Year -> Inflation rate %
-	InflationAverage float64            `json:"inflation_average"` // 10-year average inflation rate
+	VOO              map[string]float64 `json:"voo"`                      // Year -> Annual return % (S&P 500)
+	QQQ              map[string]float64 `json:"qqq"`                      // Year -> Annual return % (Nasdaq 100)
+	VTI              map[string]float64 `json:"vti"`                      // Year -> Annual return % (Total Stock Market)
+	BND              map[string]float64 `json:"bnd"`                      // Year -> Annual return % (Total Bond Market)
+	Inflation        map[string]float64 `json:"inflation"`                // Year -> Inflation rate %
+	InflationAverage float64            `json:"inflation_average"`        // 10-year average inflation rate
+	PriceReturn      bool               `json:"price_return,omitempty"`   // Returns exclude dividends
+	CloseToClose     bool               `json:"close_to_close,omitempty"` // Returns run from the prior year's last close
 }
 
 // YahooChartResponse represents the JSON response from Yahoo Finance chart API
@@ -32,33 +49,38 @@ type YahooChartResponse struct {
 		Result []struct {
 			Timestamp  []int64 `json:"timestamp"`
 			Indicators struct {
+				Quote []struct {
+					Close []*float64 `json:"close"` // nil where Yahoo returns null
+				} `json:"quote"`
 				Adjclose []struct {
-					Adjclose []float64 `json:"adjclose"`
+					Adjclose []*float64 `json:"adjclose"` // nil where Yahoo returns null
 				} `json:"adjclose"`
 			} `json:"indicators"`
 		} `json:"result"`
 	} `json:"chart"`
 }
 
-// fetchYahooFinanceData fetches historical price data from Yahoo Finance using chart API
-func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]string, error) {
+// fetchYahooFinanceData fetches historical price data from Yahoo Finance using chart API.
+// Prices are the dividend-adjusted close, or the raw close when priceOnly is set, with one
+// bar per interval ("1d", "1wk" or "1mo").
+func fetchYahooFinanceData(ticker string, startDate, endDate time.Time, interval string, priceOnly bool) ([][]string, error) {
 	// Convert to Unix timestamps
 	period1 := startDate.Unix()
 	period2 := endDate.Unix()
 
 	// Build URL using chart API (more reliable than download endpoint)
-	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=1d",
-		ticker, period1, period2)
+	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=%s",
+		ticker, period1, period2, interval)
 
 	// Create request with headers
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	// Make request
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %v", err)
@@ -82,18 +104,37 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 
 	result := chartResp.Chart.Result[0]
 	timestamps := result.Timestamp
-	adjCloses := result.Indicators.Adjclose[0].Adjclose
 
-	if len(timestamps) != len(adjCloses) {
+	// Pick the price series: raw close for price return, adjusted close for total return
+	var closes []*float64
+	column := "Adj Close"
+	if priceOnly {
+		if len(result.Indicators.Quote) == 0 {
+			return nil, fmt.Errorf("no close data returned")
+		}
+		closes = result.Indicators.Quote[0].Close
+		column = "Close"
+	} else {
+		if len(result.Indicators.Adjclose) == 0 {
+			return nil, fmt.Errorf("no adjusted close data returned")
+		}
+		closes = result.Indicators.Adjclose[0].Adjclose
+	}
+
+	if len(timestamps) != len(closes) {
 		return nil, fmt.Errorf("data length mismatch")
 	}
 
-	// Convert to CSV format: Date, Adj Close
-	records := [][]string{{"Date", "Adj Close"}}
+	// Convert to CSV format: Date, Close
+	records := [][]string{{"Date", column}}
 	for i, ts := range timestamps {
+		// Skip days Yahoo has no price for; recording 0 would show up as a -100% return
+		if closes[i] == nil || *closes[i] <= 0 {
+			continue
+		}
 		date := time.Unix(ts, 0).Format("2006-01-02")
-		adjClose := fmt.Sprintf("%.6f", adjCloses[i])
-		records = append(records, []string{date, adjClose})
+		price := fmt.Sprintf("%.6f", *closes[i])
+		records = append(records, []string{date, price})
 	}
 
 	return records, nil
@@ -122,7 +163,7 @@ func fetchInflationData(apiKey string, years int) (map[string]float64, error) {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %v", err)
@@ -153,8 +194,11 @@ func fetchInflationData(apiKey string, years int) (map[string]float64, error) {
 	return inflation, nil
 }
 
-// calculateAnnualReturns calculates annual returns from daily price data
-func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
+// calculateAnnualReturns calculates annual returns from daily, weekly or monthly price data. By default a year's
+// return runs from its first to its last close; with closeToClose it runs from the prior
+// year's last close instead (the conventional definition). Years without a prior year's close
+// in the data (such as the oldest one) are left out then, rather than mixing the definitions.
+func calculateAnnualReturns(records [][]string, closeToClose bool) (map[string]float64, error) {
 	if len(records) < 2 {
 		return nil, fmt.Errorf("insufficient data")
 	}
@@ -181,9 +225,10 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 		}
 		year := date[:4]
 
-		// Parse adjusted close price (column 1)
+		// Parse adjusted close price (column 1), skipping bad data so each year
+		// starts from its first valid price
 		adjClose, err := strconv.ParseFloat(record[1], 64)
-		if err != nil {
+		if err != nil || math.IsNaN(adjClose) || adjClose <= 0 {
 			continue
 		}
 
@@ -199,8 +244,17 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 	// Calculate annual returns
 	returns := make(map[string]float64)
 	for year, data := range yearPrices {
-		if data.firstPrice > 0 {
-			returnPct := ((data.lastPrice - data.firstPrice) / data.firstPrice) * 100
+		basePrice := data.firstPrice
+		if closeToClose {
+			basePrice = 0
+			if y, err := strconv.Atoi(year); err == nil {
+				if prior := yearPrices[strconv.Itoa(y-1)]; prior != nil {
+					basePrice = prior.lastPrice
+				}
+			}
+		}
+		if basePrice > 0 {
+			returnPct := ((data.lastPrice - basePrice) / basePrice) * 100
 			returns[year] = returnPct
 		}
 	}
@@ -211,17 +265,29 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 func main() {
 	outputFile := flag.String("o", defaultOutputFile, "Output JSON file path")
 	years := flag.Int("years", 16, "Number of years of data to fetch (default 16 for 15 complete years)")
+	flag.DurationVar(&httpTimeout, "http-timeout", httpTimeout, "Timeout for each HTTP request")
+	flag.StringVar(&userAgent, "user-agent", userAgent, "User-Agent header sent to Yahoo Finance")
+	interval := flag.String("interval", "1d", "Yahoo Finance bar size: 1d, 1wk, or 1mo (weekly and monthly are much smaller, and imply -close-to-close)")
+	priceReturn := flag.Bool("price-return", false, "Use the raw close (price return, no dividends) instead of the dividend-adjusted close")
+	closeToCloseReturns := flag.Bool("close-to-close", false, "Measure each year's return from the prior year's last close instead of the year's first close (implied by -interval 1wk or 1mo)")
 	flag.Parse()
 
+	if *interval != "1d" && *interval != "1wk" && *interval != "1mo" {
+		fmt.Fprintf(os.Stderr, "Error: invalid -interval %q (expected 1d, 1wk, or 1mo)\n", *interval)
+		os.Exit(1)
+	}
+
 	fmt.Println("Fetching market data from Yahoo Finance...")
 
 	md := &MarketData{
-		VOO:       make(map[string]float64),
-		QQQ:       make(map[string]float64),
-		VTI:       make(map[string]float64),
-		BND:       make(map[string]float64),
-		Inflation: make(map[string]float64),
+		VOO:         make(map[string]float64),
+		QQQ:         make(map[string]float64),
+		VTI:         make(map[string]float64),
+		BND:         make(map[string]float64),
+		Inflation:   make(map[string]float64),
+		PriceReturn: *priceReturn,
 	}
+	md.CloseToClose = *closeToCloseReturns || *interval != "1d"
 
 	// Fetch data for specified years
 	startDate := time.Now().AddDate(-*years, 0, 0)
@@ -243,13 +309,15 @@ func main() {
 	for i, ticker := range tickers {
 		fmt.Printf("  Fetching %s...\n", ticker.name)
 
-		records, err := fetchYahooFinanceData(ticker.symbol, startDate, endDate)
+		records, err := fetchYahooFinanceData(ticker.symbol, startDate, endDate, *interval, *priceReturn)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", ticker.symbol, err)
 			os.Exit(1)
 		}
 
-		returns, err := calculateAnnualReturns(records)
+		// A year's first weekly or monthly bar closes after the year has started, so measure
+		// from the prior year's last close to cover the whole year
+		returns, err := calculateAnnualReturns(records, md.CloseToClose)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error calculating %s returns: %v\n", ticker.symbol, err)
 			os.Exit(1)
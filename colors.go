@@ -1,6 +1,14 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 // Monokai Pro color scheme
 var (
@@ -8,6 +16,8 @@ var (
 	MonokaiOrange = lipgloss.Color("#FC9867") // Secondary accent - group headers
 	MonokaiCyan   = lipgloss.Color("81")      // Tertiary accent - labels, table headers
 	MonokaiBorder = lipgloss.Color("238")     // Borders
+	MonokaiGreen  = lipgloss.Color("#A9DC76") // Favorable values
+	MonokaiRed    = lipgloss.Color("#FF6188") // Unfavorable values
 
 	// Adaptive colors for different terminal backgrounds
 	MonokaiAdaptiveText = lipgloss.AdaptiveColor{
@@ -19,3 +29,98 @@ var (
 		Dark:  "250", // Light gray for dark backgrounds
 	}
 )
+
+// Theme holds the colors used by the tables, input parameters and the form
+type Theme struct {
+	Primary   lipgloss.TerminalColor // Titles, focused elements
+	Secondary lipgloss.TerminalColor // Group headers
+	Accent    lipgloss.TerminalColor // Labels, table headers
+	Border    lipgloss.TerminalColor // Table and dialog borders
+	Text      lipgloss.TerminalColor // Regular text and table rows
+	Muted     lipgloss.TerminalColor // Notes and help text
+	Positive  lipgloss.TerminalColor // Renting/keeping wins
+	Negative  lipgloss.TerminalColor // Buying/selling wins
+}
+
+// themes maps --theme names to their palettes
+var themes = map[string]Theme{
+	"monokai": {
+		Primary:   MonokaiPink,
+		Secondary: MonokaiOrange,
+		Accent:    MonokaiCyan,
+		Border:    MonokaiBorder,
+		Text:      MonokaiAdaptiveText,
+		Muted:     MonokaiGrey,
+		Positive:  MonokaiGreen,
+		Negative:  MonokaiRed,
+	},
+	"solarized": {
+		Primary:   lipgloss.Color("#D33682"), // Magenta
+		Secondary: lipgloss.Color("#CB4B16"), // Orange
+		Accent:    lipgloss.Color("#268BD2"), // Blue
+		Border:    lipgloss.Color("#93A1A1"), // Base1
+		Text: lipgloss.AdaptiveColor{
+			Light: "#586E75", // Base01 for light backgrounds
+			Dark:  "#93A1A1", // Base1 for dark backgrounds
+		},
+		Muted: lipgloss.AdaptiveColor{
+			Light: "#657B83", // Base00 for light backgrounds
+			Dark:  "#839496", // Base0 for dark backgrounds
+		},
+		Positive: lipgloss.Color("#859900"), // Green
+		Negative: lipgloss.Color("#DC322F"), // Red
+	},
+	"mono": {
+		// Terminal default foreground everywhere; bold/italic still provide hierarchy
+		Primary:   lipgloss.NoColor{},
+		Secondary: lipgloss.NoColor{},
+		Accent:    lipgloss.NoColor{},
+		Border:    lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Positive:  lipgloss.NoColor{},
+		Negative:  lipgloss.NoColor{},
+	},
+}
+
+// activeTheme is the palette selected with --theme (defaults to monokai)
+var activeTheme = themes["monokai"]
+
+// setTheme selects a theme by name and refreshes the form styles
+func setTheme(name string) error {
+	theme, ok := themes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(themes))
+		for themeName := range themes {
+			names = append(names, themeName)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	activeTheme = theme
+	initFormStyles()
+	return nil
+}
+
+// noColor disables all ANSI styling (set by --no-color or the NO_COLOR env var)
+var noColor bool
+
+// applyColorMode strips colors from the default renderer (used by the form) when noColor is set
+func applyColorMode() {
+	if os.Getenv("NO_COLOR") != "" {
+		noColor = true
+	}
+	if noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// newRenderer returns a stdout renderer for tables, without colors when noColor is set
+func newRenderer() *lipgloss.Renderer {
+	re := lipgloss.NewRenderer(os.Stdout)
+	if noColor {
+		re.SetColorProfile(termenv.Ascii)
+	}
+	return re
+}
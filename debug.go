@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"calculator/calc"
+)
+
+// debugMonth is the month whose intermediate calculations --debug dumps (0 disables it)
+var debugMonth int
+
+// debugLog writes to stderr only when --debug is set, so the tables on stdout are unchanged
+var debugLog = log.New(io.Discard, "debug: ", 0)
+
+// enableDebug routes the debug logger to stderr
+func enableDebug() {
+	debugLog.SetOutput(os.Stderr)
+}
+
+// dumpDebugMonth logs the intermediate values behind the tables for one month, in full
+// precision, so the math can be checked by hand
+func dumpDebugMonth(scenario *calc.Scenario, month int, isSellVsKeep bool) {
+	if month <= 0 {
+		return
+	}
+	if month > scenario.Months() {
+		debugLog.Printf("month %d is past the %d-month projection", month, scenario.Months())
+		return
+	}
+	debugLog.Printf("month %d", month)
+
+	loan := scenario.AmortizationAt(month)
+	debugLog.Printf("  loan payment: interest %.2f, principal %.2f, balance %.2f",
+		loan.InterestPaid, loan.PrincipalPaid, loan.Balance)
+	debugLog.Printf("  paid to date: interest %.2f, principal %.2f",
+		loan.CumulativeInterest, loan.CumulativePrincipal)
+	debugLog.Printf("  monthly costs: buying %.2f, renting %.2f",
+		scenario.BuyingCostAt(month), scenario.RentingCostAt(month))
+
+	sale := scenario.SaleProceedsAt(month)
+	debugLog.Printf("  appreciated value %.2f, improvements to date %.2f",
+		sale.SalePrice, scenario.ImprovementsBefore(month))
+	debugLog.Printf("  sale: costs %.2f, loan payoff %.2f, capital gains %.2f, tax %.2f, net proceeds %.2f",
+		sale.SellingCosts, sale.LoanPayoff, sale.CapitalGains, sale.Tax, sale.NetProceeds)
+
+	if isSellVsKeep {
+		keep := scenario.KeepPositionAt(month)
+		debugLog.Printf("  keep: investment %.2f, real costs %.2f, net position %.2f, net worth %.2f",
+			keep.InvestmentValue, keep.RealCosts, keep.NetPosition, scenario.KeepNetWorthAt(month))
+		debugLog.Printf("  sell: net worth %.2f", scenario.SellNetWorthAt(month))
+		return
+	}
+
+	buying := scenario.NetWorthAt(month)
+	debugLog.Printf("  buy: expenditure %.2f, net worth %.2f", buying.TotalExpenditure, buying.NetWorth)
+	renting := scenario.RentingNetWorthAt(month)
+	debugLog.Printf("  rent: savings %.2f, market return %.2f, net worth %.2f",
+		renting.CumulativeSavings, renting.MarketReturn, renting.NetWorth)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envPrefix starts the environment variables that set inputs: RENTOBUY_ followed by the
+// input key in upper case (e.g., RENTOBUY_PURCHASE_PRICE for purchase_price)
+const envPrefix = "RENTOBUY_"
+
+// inputKeys returns the keys of all form inputs
+func inputKeys() map[string]*FormField {
+	return NewFormModel(nil, nil).fieldsMap
+}
+
+// envInputs returns the inputs set through environment variables, keyed by input key.
+// Variables with the prefix that don't name an input are reported on stderr.
+func envInputs() map[string]string {
+	keys := inputKeys()
+
+	inputs := make(map[string]string)
+	var unknown []string
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, envPrefix) {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(name, envPrefix))
+		if _, ok := keys[key]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		inputs[key] = value
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unknown input environment variables: %s\n", strings.Join(unknown, ", "))
+	}
+	return inputs
+}
+
+// argInputs parses key=value command line arguments (e.g., purchase_price=450k) into
+// inputs. Keys that don't name an input are reported on stderr and skipped.
+func argInputs(args []string) (map[string]string, error) {
+	keys := inputKeys()
+
+	inputs := make(map[string]string)
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", arg)
+		}
+		if _, ok := keys[key]; !ok {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unknown input %q\n", key)
+			continue
+		}
+		inputs[key] = strings.TrimSpace(value)
+	}
+	return inputs, nil
+}
+
+// withInputs returns the base inputs with the overrides on top
+func withInputs(base, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return base
+	}
+	inputs := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		inputs[key] = value
+	}
+	for key, value := range overrides {
+		inputs[key] = value
+	}
+	return inputs
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"calculator/calc"
 )
 
 // FormField represents a single input field in the form
@@ -39,6 +41,7 @@ type FormModel struct {
 	values        map[string]string
 	err           error
 	marketData    *MarketData
+	benchmark     string // Market average last filled into the investment return with Ctrl+A
 	dialogMode    DialogMode
 	dialogInput   textinput.Model
 	profileList   []string
@@ -46,14 +49,28 @@ type FormModel struct {
 }
 
 var (
-	focusedStyle = lipgloss.NewStyle().Foreground(MonokaiPink).Bold(true)
-	blurredStyle = lipgloss.NewStyle().Foreground(MonokaiAdaptiveText)
-	cursorStyle  = focusedStyle.Copy()
-	helpStyle    = lipgloss.NewStyle().Foreground(MonokaiGrey)
-	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(MonokaiPink)
-	groupStyle   = lipgloss.NewStyle().Bold(true).Foreground(MonokaiOrange)
+	focusedStyle lipgloss.Style
+	blurredStyle lipgloss.Style
+	cursorStyle  lipgloss.Style
+	helpStyle    lipgloss.Style
+	titleStyle   lipgloss.Style
+	groupStyle   lipgloss.Style
 )
 
+func init() {
+	initFormStyles()
+}
+
+// initFormStyles builds the form styles from the active theme
+func initFormStyles() {
+	focusedStyle = lipgloss.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	blurredStyle = lipgloss.NewStyle().Foreground(activeTheme.Text)
+	cursorStyle = focusedStyle.Copy()
+	helpStyle = lipgloss.NewStyle().Foreground(activeTheme.Muted)
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(activeTheme.Primary)
+	groupStyle = lipgloss.NewStyle().Bold(true).Foreground(activeTheme.Secondary)
+}
+
 // FieldGroup represents a group of related fields
 type FieldGroup struct {
 	Name     string
@@ -85,8 +102,9 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Name:     "ECONOMIC ASSUMPTIONS",
 			Scenario: "both",
 			Fields: []FormField{
-				makeField("inflation_rate", "Inflation Rate (%)", "Annual inflation for all recurring costs", defaults),
-				makeField("investment_return_rate", "Investment Return Rate (%)", "Expected return on investments. Market averages shown below", defaults),
+				makeField("inflation_rate", "Inflation Rate (%)", "Annual inflation for all recurring costs. Comma-separated values apply to first years, last value for all remaining years (e.g., '6,4,3')", "e.g. 3", defaults),
+				makeField("investment_return_rate", "Investment Return Rate (%)", "Expected return on investments. Comma-separated for different years (e.g., '-10,8' = -10% yr1, 8% yr2+). Market averages shown below", "e.g. 7", defaults),
+				makeField("investment_fee", "Investment Fees (%)", "Yearly expense ratio/advisory fees taken from the return (e.g., 0.03 for an index fund, 1 for a managed account)", "e.g. 0.1", defaults),
 				makeToggleField("include_30year", "Include 30-Year Projections", "Toggle to show 15y, 20y, 30y periods (default: 10y max)", defaults),
 			},
 		},
@@ -94,40 +112,101 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Name:     "BUYING",
 			Scenario: "buy_vs_rent",
 			Fields: []FormField{
-				makeField("purchase_price", "Asset Purchase Price ($)", "Initial purchase price of the asset", defaults),
-				makeField("loan_amount", "Loan Amount ($)", "Total mortgage/loan amount", defaults),
-				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate (e.g., 6.5)", defaults),
-				makeField("loan_term", "Loan Term", "Loan duration (e.g., 5y, 30y)", defaults),
-				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly insurance cost", defaults),
-				makeField("annual_taxes", "Other Annual Costs ($)", "Maintenance costs, etc.", defaults),
-				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly expenses. Typically include utilities, HOA, etc. Can be negative if earning income, e.g., -4K.", defaults),
-				makeField("appreciation_rate", "Appreciation Rate (%)", "Annual rate (can be negative for depreciation). Comma-separated values apply to first years, last value for all remaining years (e.g., '10,5,3' = 10% yr1, 5% yr2, 3% yr3+)", defaults),
+				makeField("purchase_price", "Asset Purchase Price ($)", "Initial purchase price of the asset", "e.g. 450k", defaults),
+				makeField("transfer_tax_pct", "Transfer Tax (%)", "Transfer/recording tax as % of price, paid at purchase (and at sale if enabled under SELLING)", "e.g. 1", defaults),
+				makeField("seller_concessions", "Seller Concessions ($)", "Optional credit from the seller at closing: lowers your cash at purchase, not the loan (0 = none)", "e.g. 10k", defaults),
+				makeField("gift_funds", "Gift Funds ($)", "Optional gift toward the downpayment: you supply that much less cash (0 = none)", "e.g. 20k", defaults),
+				makeField("stamp_duty", "Stamp Duty ($ or bands)", "Paid at purchase: an amount, or limit:rate bands with a final rate for the rest (e.g., UK SDLT '125K:0,250K:2,925K:5,1.5M:10,12')", "e.g. 15k or 125k:0,250k:2,5", defaults),
+				makeField("square_feet", "Square Feet", "Optional living area to show monthly cost per square foot", "e.g. 1500", defaults),
+				makeField("loan_amount", "Loan Amount ($)", "Total mortgage/loan amount", "e.g. 360k", defaults),
+				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate (e.g., 6.5)", "e.g. 6.5", defaults),
+				makeField("loan_term", "Loan Term", "Loan duration (e.g., 5y, 30y)", "e.g. 30y", defaults),
+				makeField("loan_start", "Loan Start (YYYY-MM)", "Optional month of the first payment, to total interest paid by calendar (tax) year", "e.g. 2025-03", defaults),
+				makeField("day_count", "Interest Day Count", "30/360 (default) or actual/365 to match a lender's statement. actual/365 needs a loan start", "30/360", defaults),
+				makeField("payment_frequency", "Payment Frequency", "monthly (default), semimonthly, biweekly or weekly. Interest accrues per payment period", "monthly", defaults),
+				makeField("compounding", "Rate Compounding", "monthly (default, US) or semiannual (Canadian fixed mortgages)", "monthly", defaults),
+				makeField("fixed_period", "Fixed Rate Period", "Optional time the loan rate is fixed for (e.g., 2y, 5y), after which it reverts to the SVR", "e.g. 2y", defaults),
+				makeField("svr_rate", "SVR Rate (%)", "Standard variable rate after the fixed period (UK). Empty = the loan rate for the whole term", "e.g. 7.5", defaults),
+				makeField("recast_amount", "Recast Lump Sum ($)", "Optional principal prepayment that recasts the loan to a lower payment (0 = none)", "e.g. 50k", defaults),
+				makeField("recast_month", "Recast Month", "Month number of the lump-sum payment (e.g., 24)", "e.g. 24", defaults),
+				makeField("overpayment_cap_pct", "Overpayment Cap (%)", "Optional yearly cap on overpayments as % of the balance (e.g., 10 on UK fixed deals): at most that much of the lump sum is applied. Empty = no cap", "e.g. 10", defaults),
+				makeField("overpayment_penalty_pct", "Early Repayment Charge (%)", "Charge on the part of the lump sum above the overpayment cap, which isn't applied", "e.g. 3", defaults),
+				makeField("offset_savings", "Offset Savings ($)", "Optional savings linked to an offset mortgage (UK): no interest on that much of the balance, so the same payment clears the loan sooner", "e.g. 40k", defaults),
+				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly insurance cost", "e.g. 12k", defaults),
+				makeField("property_tax_rate", "Property Tax Rate (%)", "Optional yearly property tax as % of the home's value, reassessed each year. Tax & Insurance then covers insurance only", "e.g. 1.1", defaults),
+				makeField("tax_reassessment_cap", "Tax Reassessment Cap (%)", "Optional max yearly growth of Tax & Insurance (e.g., 2 for Prop 13). Empty = grows with inflation", "e.g. 2", defaults),
+				makeField("annual_taxes", "Other Annual Costs ($)", "Maintenance costs, etc.", "e.g. 5k", defaults),
+				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly expenses. Typically include utilities, HOA, etc. Can be negative if earning income, e.g., -4K.", "e.g. 400", defaults),
+				makeField("replacement_reserve", "Replacement Reserve ($/mo)", "Monthly set-aside for major replacements (roof, HVAC, appliances), inflated yearly", "e.g. 200", defaults),
+				makeField("buy_utilities", "Utilities ($/mo)", "Monthly heating, cooling, water, etc. when owning (if not in Monthly Expenses)", "e.g. 300", defaults),
+				makeField("ground_rent", "Ground Rent ($/yr)", "Leasehold ground rent paid to the freeholder (e.g., UK flats)", "e.g. 250", defaults),
+				makeField("ground_rent_increase", "Ground Rent Increase (%)", "Yearly growth of the ground rent (0 = fixed)", "e.g. 0", defaults),
+				makeField("service_charge", "Service Charge ($/yr)", "Leasehold service charge for building upkeep and management", "e.g. 2k", defaults),
+				makeField("service_charge_increase", "Service Charge Increase (%)", "Yearly growth of the service charge. Empty = grows with inflation", "e.g. 5", defaults),
+				makeField("council_tax", "Council Tax ($/yr)", "UK council tax paid by the owner (0 = none)", "e.g. 2k", defaults),
+				makeField("council_tax_increase", "Council Tax Increase (%)", "Yearly growth of the council tax. Empty = grows with inflation", "e.g. 5", defaults),
+				makeToggleField("appreciation_mode", "Appreciation in Dollars", "Toggle to enter appreciation as a fixed dollar amount per year (e.g., '20K') instead of a percentage", defaults),
+				makeField("gross_monthly_income", "Gross Monthly Income ($)", "Optional pre-tax household income, to check the housing cost against the 28%/36% debt-to-income guidelines", "e.g. 15k", defaults),
+				makeToggleField("investment_property", "Investment Property", "Toggle to take 27.5-year straight-line depreciation (recaptured at 25% on sale)", defaults),
+				makeField("income_tax_rate", "Income Tax Rate (%)", "Marginal income tax rate for the depreciation tax shield (investment property only)", "e.g. 32", defaults),
+				makeField("rental_income", "Rental Income ($/mo)", "Rent collected from tenants, inflated yearly (investment property only). Shows the cash-on-cash return", "e.g. 2.5k", defaults),
+				makeField("capital_improvements", "Capital Improvements ($)", "Renovations that add value and raise the tax basis. Comma-separated by year (e.g., '50K' = year 1 only, '0,0,30K' = year 3)", "e.g. 0,0,30k", defaults),
+				makeField("rebuy_year", "Sell & Re-buy After (years)", "Optional: sell after this many years and buy another home with the proceeds (0 = never)", "e.g. 7", defaults),
+				makeField("rebuy_price", "Re-buy Price ($)", "Price of the next home at the time of the re-buy. Financed at the same loan rate and term", "e.g. 800k", defaults),
+				makeField("appreciation_rate", "Appreciation Rate (% or $)", "Annual rate (can be negative for depreciation). Comma-separated values apply to first years, last value for all remaining years (e.g., '10,5,3' = 10% yr1, 5% yr2, 3% yr3+; '3x5,1' = 3% for 5 years, then 1%; '5..2:4' = 5% down to 2% over 4 years)", "e.g. 3 or 5,3,2", defaults),
 			},
 		},
 		{
 			Name:     "ASSET",
 			Scenario: "sell_vs_keep",
 			Fields: []FormField{
-				makeField("purchase_price", "Asset Purchase Price ($)", "What you originally paid for the asset (for capital gains)", defaults),
-				makeField("current_market_value", "Current Market Value ($)", "What the asset is worth today", defaults),
-				makeField("loan_amount", "Original Loan Amount ($)", "The original loan amount when purchased (we'll calculate remaining balance)", defaults),
-				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate on existing loan", defaults),
-				makeField("loan_term", "Loan Term", "Original loan duration when started (e.g., 30y)", defaults),
-				makeField("remaining_loan_term", "Remaining Loan Term", "Time left on loan (e.g., 25y)", defaults),
-				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly costs if keeping", defaults),
-				makeField("annual_taxes", "Other Annual Costs ($)", "Taxes, HOA fees, etc. if keeping", defaults),
-				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly costs if keeping", defaults),
-				makeField("appreciation_rate", "Appreciation Rate (%)", "Annual rate if keeping. Comma-separated for different years", defaults),
+				makeField("purchase_price", "Asset Purchase Price ($)", "What you originally paid for the asset (for capital gains)", "e.g. 450k", defaults),
+				makeField("current_market_value", "Current Market Value ($)", "What the asset is worth today", "e.g. 600k", defaults),
+				makeField("transfer_tax_pct", "Transfer Tax (%)", "Transfer tax as % of sale price (applies if enabled under SELLING)", "e.g. 1", defaults),
+				makeField("loan_amount", "Original Loan Amount ($)", "The original loan amount when purchased (we'll calculate remaining balance)", "e.g. 360k", defaults),
+				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate on existing loan", "e.g. 6.5", defaults),
+				makeField("loan_term", "Loan Term", "Original loan duration when started (e.g., 30y)", "e.g. 30y", defaults),
+				makeField("remaining_loan_term", "Remaining Loan Term", "Time left on loan (e.g., 25y)", "e.g. 25y", defaults),
+				makeField("compounding", "Rate Compounding", "monthly (default, US) or semiannual (Canadian fixed mortgages)", "monthly", defaults),
+				makeField("fixed_period", "Fixed Rate Period", "Optional time the loan rate is fixed for (e.g., 2y, 5y), after which it reverts to the SVR", "e.g. 2y", defaults),
+				makeField("svr_rate", "SVR Rate (%)", "Standard variable rate after the fixed period (UK). Empty = the loan rate for the whole term", "e.g. 7.5", defaults),
+				makeField("offset_savings", "Offset Savings ($)", "Optional savings linked to an offset mortgage (UK): no interest on that much of the balance, so the same payment clears the loan sooner", "e.g. 40k", defaults),
+				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly costs if keeping", "e.g. 12k", defaults),
+				makeField("property_tax_rate", "Property Tax Rate (%)", "Optional yearly property tax as % of the home's value, reassessed each year. Tax & Insurance then covers insurance only", "e.g. 1.1", defaults),
+				makeField("tax_reassessment_cap", "Tax Reassessment Cap (%)", "Optional max yearly growth of Tax & Insurance (e.g., 2 for Prop 13). Empty = grows with inflation", "e.g. 2", defaults),
+				makeField("annual_taxes", "Other Annual Costs ($)", "Taxes, HOA fees, etc. if keeping", "e.g. 5k", defaults),
+				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly costs if keeping", "e.g. 400", defaults),
+				makeField("replacement_reserve", "Replacement Reserve ($/mo)", "Monthly set-aside for major replacements if keeping", "e.g. 200", defaults),
+				makeField("buy_utilities", "Utilities ($/mo)", "Monthly utilities if keeping", "e.g. 300", defaults),
+				makeField("ground_rent", "Ground Rent ($/yr)", "Leasehold ground rent paid to the freeholder (e.g., UK flats)", "e.g. 250", defaults),
+				makeField("ground_rent_increase", "Ground Rent Increase (%)", "Yearly growth of the ground rent (0 = fixed)", "e.g. 0", defaults),
+				makeField("service_charge", "Service Charge ($/yr)", "Leasehold service charge if keeping", "e.g. 2k", defaults),
+				makeField("service_charge_increase", "Service Charge Increase (%)", "Yearly growth of the service charge. Empty = grows with inflation", "e.g. 5", defaults),
+				makeField("council_tax", "Council Tax ($/yr)", "UK council tax paid by the owner (0 = none)", "e.g. 2k", defaults),
+				makeField("council_tax_increase", "Council Tax Increase (%)", "Yearly growth of the council tax. Empty = grows with inflation", "e.g. 5", defaults),
+				makeToggleField("appreciation_mode", "Appreciation in Dollars", "Toggle to enter appreciation as a fixed dollar amount per year (e.g., '20K') instead of a percentage", defaults),
+				makeField("capital_improvements", "Capital Improvements ($)", "Future renovations if keeping. Comma-separated by year (e.g., '0,0,30K' = year 3)", "e.g. 0,0,30k", defaults),
+				makeField("appreciation_rate", "Appreciation Rate (% or $)", "Annual rate if keeping. Comma-separated for different years", "e.g. 3 or 5,3,2", defaults),
 			},
 		},
 		{
 			Name:     "RENTING",
 			Scenario: "buy_vs_rent",
 			Fields: []FormField{
-				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit", defaults),
-				makeField("monthly_rent", "Monthly Rent ($)", "Base monthly rent amount", defaults),
-				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental-related costs", defaults),
-				makeField("other_annual_costs", "Other Annual Costs ($)", "Additional yearly costs for renting", defaults),
+				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit", "e.g. 4k", defaults),
+				makeToggleField("deposit_earns_returns", "Deposit Earns Returns", "Toggle to grow the recoverable deposit at the investment return rate (default: returned without growth)", defaults),
+				makeField("monthly_rent", "Monthly Rent ($)", "Base monthly rent amount", "e.g. 2.2k", defaults),
+				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental-related costs", "e.g. 1k", defaults),
+				makeField("other_annual_costs", "Other Annual Costs ($)", "Additional yearly costs for renting", "e.g. 500", defaults),
+				makeField("renters_insurance", "Renters Insurance ($)", "Yearly renters insurance premium", "e.g. 200", defaults),
+				makeField("rent_utilities", "Utilities ($/mo)", "Monthly utilities when renting", "e.g. 200", defaults),
+				makeField("move_cost", "Moving Cost ($)", "Cost of each move (movers, deposit churn, etc.). 0 if you don't expect to move", "e.g. 3k", defaults),
+				makeField("move_every_years", "Move Every (years)", "How often you expect to move as a renter (e.g., 3)", "e.g. 3", defaults),
+				makeField("lease_break_penalty", "Lease Break Penalty ($)", "Paid once if the projection period ends mid-lease (12-month leases)", "e.g. 4k", defaults),
+				makeField("rent_increase_cap", "Rent Increase Cap (%)", "Optional max yearly rent increase under rent control (e.g., 3). Empty = rent grows with inflation", "e.g. 3", defaults),
+				makeField("rent_free_months", "Rent-Free Months", "Free months offered at the start of the lease (e.g., 2)", "e.g. 1", defaults),
+				makeField("broker_fee", "Broker Fee ($ or %)", "Paid once at move-in, in dollars or as % of annual rent (e.g., '15%')", "e.g. 2k or 15%", defaults),
+				makeField("rent_upfront_months", "Rent Paid Upfront (months)", "Months of rent due at move-in besides the deposit (e.g., 2 for first and last month). Ties up cash that isn't invested", "e.g. 2", defaults),
 			},
 		},
 		{
@@ -135,9 +214,18 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Scenario: "sell_vs_keep",
 			Fields: []FormField{
 				makeToggleField("include_renting_sell", "Include Renting Analysis", "Toggle if selling means you'll need to rent", defaults),
-				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit if selling", defaults),
-				makeField("monthly_rent", "Monthly Rent ($)", "Monthly rent if selling", defaults),
-				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental costs if selling", defaults),
+				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit if selling", "e.g. 4k", defaults),
+				makeToggleField("deposit_earns_returns", "Deposit Earns Returns", "Toggle to grow the recoverable deposit at the investment return rate (default: returned without growth)", defaults),
+				makeField("monthly_rent", "Monthly Rent ($)", "Monthly rent if selling", "e.g. 2.2k", defaults),
+				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental costs if selling", "e.g. 1k", defaults),
+				makeField("rent_utilities", "Utilities ($/mo)", "Monthly utilities when renting", "e.g. 200", defaults),
+				makeField("move_cost", "Moving Cost ($)", "Cost of each move if renting. 0 if you don't expect to move", "e.g. 3k", defaults),
+				makeField("move_every_years", "Move Every (years)", "How often you expect to move as a renter (e.g., 3)", "e.g. 3", defaults),
+				makeField("lease_break_penalty", "Lease Break Penalty ($)", "Paid once if the projection period ends mid-lease (12-month leases)", "e.g. 4k", defaults),
+				makeField("rent_increase_cap", "Rent Increase Cap (%)", "Optional max yearly rent increase under rent control (e.g., 3). Empty = rent grows with inflation", "e.g. 3", defaults),
+				makeField("rent_free_months", "Rent-Free Months", "Free months offered at the start of the lease (e.g., 2)", "e.g. 1", defaults),
+				makeField("broker_fee", "Broker Fee ($ or %)", "Paid once at move-in, in dollars or as % of annual rent (e.g., '15%')", "e.g. 2k or 15%", defaults),
+				makeField("rent_upfront_months", "Rent Paid Upfront (months)", "Months of rent due at move-in besides the deposit (e.g., 2 for first and last month). Ties up cash that isn't invested", "e.g. 2", defaults),
 			},
 		},
 		{
@@ -145,10 +233,11 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Scenario: "both",
 			Fields: []FormField{
 				makeToggleField("include_selling", "Include Selling Analysis", "Toggle to enable/disable selling analysis (BUY vs RENT only)", defaults),
-				makeField("agent_commission", "Agent Commission (%)", "Percentage of sale price paid to agents", defaults),
-				makeField("staging_costs", "Staging/Selling Costs ($)", "Fixed costs to prepare and sell", defaults),
-				makeField("tax_free_limit", "Tax-Free Gains Limit ($)", "Capital gains exempt from tax. Comma-separated for different years (e.g., '500K,0K' = 500K year 1, 0 year 2+)", defaults),
-				makeField("capital_gains_tax", "Capital Gains Tax Rate (%)", "Long-term capital gains tax rate", defaults),
+				makeField("agent_commission", "Agent Commission (%)", "Percentage of sale price paid to agents", "e.g. 5", defaults),
+				makeField("staging_costs", "Staging/Selling Costs ($)", "Fixed costs to prepare and sell", "e.g. 10k", defaults),
+				makeToggleField("transfer_tax_on_sale", "Transfer Tax at Sale", "Toggle if the transfer tax is also charged at sale", defaults),
+				makeField("tax_free_limit", "Tax-Free Gains Limit ($)", "Capital gains exempt from tax. Comma-separated for different years (e.g., '500K,0K' = 500K year 1, 0 year 2+)", "e.g. 500k or 500k,0", defaults),
+				makeField("capital_gains_tax", "Capital Gains Tax Rate (%)", "Long-term capital gains tax rate", "e.g. 20", defaults),
 			},
 		},
 	}
@@ -188,13 +277,15 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 	}
 }
 
-func makeField(key, label, help string, defaults map[string]string) FormField {
+// makeField creates a text field; the placeholder shows an example of the expected format
+// while the field is empty
+func makeField(key, label, help, placeholder string, defaults map[string]string) FormField {
 	ti := textinput.New()
-	ti.Placeholder = "0"
+	ti.Placeholder = placeholder
 	ti.CharLimit = 32
 	ti.Width = 30  // Fixed width to prevent jumping
 	ti.Prompt = ""  // Disable built-in prompt, we'll use our own caret in the label
-	ti.TextStyle = lipgloss.NewStyle().Foreground(MonokaiAdaptiveText)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(activeTheme.Text)
 	ti.Cursor.Style = focusedStyle
 
 	if val, ok := defaults[key]; ok {
@@ -265,10 +356,13 @@ func (m FormModel) isFieldVisible(fieldIndex int) bool {
 	// Find which group this field belongs to
 	currentIndex := 0
 	for _, group := range m.groups {
-		for range group.Fields {
+		for i := range group.Fields {
 			if currentIndex == fieldIndex {
 				// Check if this group is visible in current scenario
-				return group.Scenario == "both" || group.Scenario == selectedScenario
+				if group.Scenario != "both" && group.Scenario != selectedScenario {
+					return false
+				}
+				return !m.isSellingFieldHidden(group.Fields[i].Key, selectedScenario)
 			}
 			currentIndex++
 		}
@@ -277,6 +371,23 @@ func (m FormModel) isFieldVisible(fieldIndex int) bool {
 	return false
 }
 
+// isSellingFieldHidden reports whether a selling detail field should be hidden because
+// selling analysis is turned off. SELL vs KEEP always needs these fields.
+func (m FormModel) isSellingFieldHidden(key, selectedScenario string) bool {
+	switch key {
+	case "agent_commission", "staging_costs", "transfer_tax_on_sale", "tax_free_limit", "capital_gains_tax":
+	default:
+		return false
+	}
+
+	if selectedScenario != "buy_vs_rent" {
+		return false
+	}
+
+	sellingField, ok := m.fieldsMap["include_selling"]
+	return ok && !sellingField.Toggled
+}
+
 func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -333,6 +444,24 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "ctrl+a":
+			// Fill the investment return with the next benchmark's market average
+			if field := m.fields[m.currentField]; field.Key == "investment_return_rate" {
+				if avg := calculateMarketAverages(m.marketData); avg.VOO > 0 {
+					benchmarks := marketBenchmarks(avg)
+					next := 0
+					for i, benchmark := range benchmarks {
+						if benchmark.name == m.benchmark {
+							next = (i + 1) % len(benchmarks)
+						}
+					}
+					m.benchmark = benchmarks[next].name
+					field.Input.SetValue(fmt.Sprintf("%.1f", benchmarks[next].rate))
+					field.Input.CursorEnd()
+					return m, nil
+				}
+			}
+
 		case "ctrl+k":
 			// Save values and submit
 			for _, field := range m.fields {
@@ -456,6 +585,11 @@ func (m FormModel) View() string {
 			groupField := &group.Fields[i]
 			field := m.fieldsMap[groupField.Key]
 
+			// Skip selling details until selling analysis is enabled
+			if m.isSellingFieldHidden(field.Key, selectedScenario) {
+				continue
+			}
+
 			// Render input or toggle
 			var input string
 			if field.IsToggle {
@@ -494,18 +628,20 @@ func (m FormModel) View() string {
 
 			// Show market averages after investment return rate field
 			if field.Key == "investment_return_rate" && m.marketData != nil && len(m.marketData.VOO) > 0 {
-				vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(m.marketData)
-				if vooAvg > 0 {
-					tickerStyle := lipgloss.NewStyle().Foreground(MonokaiCyan)
+				avg := calculateMarketAverages(m.marketData)
+				if avg.VOO > 0 {
+					tickerStyle := lipgloss.NewStyle().Foreground(activeTheme.Accent)
 					prefix := helpStyle.Render("    Market Averages (10y): ")
 					tickers := fmt.Sprintf("%s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%",
-						tickerStyle.Render("VOO"), vooAvg,
-						tickerStyle.Render("QQQ"), qqqAvg,
-						tickerStyle.Render("VTI"), vtiAvg,
-						tickerStyle.Render("BND"), bndAvg,
-						tickerStyle.Render("60/40"), mix6040Avg)
+						tickerStyle.Render("VOO"), avg.VOO,
+						tickerStyle.Render("QQQ"), avg.QQQ,
+						tickerStyle.Render("VTI"), avg.VTI,
+						tickerStyle.Render("BND"), avg.BND,
+						tickerStyle.Render("60/40"), avg.Mix6040)
 					b.WriteString(prefix + tickers)
 					b.WriteString("\n")
+					b.WriteString(helpStyle.Render("    " + m.benchmarkHint(avg)))
+					b.WriteString("\n")
 				}
 			}
 		}
@@ -519,6 +655,11 @@ func (m FormModel) View() string {
 		fieldIndex += len(group.Fields)
 	}
 
+	// Live monthly cost summary from the current field values
+	b.WriteString("\n")
+	b.WriteString(m.renderCostSummary(selectedScenario))
+	b.WriteString("\n")
+
 	// Show help text for current field at the bottom
 	currentField := m.fields[m.currentField]
 	b.WriteString("\n")
@@ -542,6 +683,133 @@ func (m FormModel) View() string {
 	return result
 }
 
+// marketBenchmark is a market average Ctrl+A can fill into the investment return
+type marketBenchmark struct {
+	name string
+	rate float64
+}
+
+// marketBenchmarks lists the market averages in the order Ctrl+A cycles through them
+func marketBenchmarks(avg MarketAverages) []marketBenchmark {
+	return []marketBenchmark{
+		{"VOO", avg.VOO},
+		{"QQQ", avg.QQQ},
+		{"VTI", avg.VTI},
+		{"BND", avg.BND},
+		{"60/40", avg.Mix6040},
+	}
+}
+
+// benchmarkHint tells which benchmark filled the investment return, while it still holds that
+// average, or how to fill one
+func (m FormModel) benchmarkHint(avg MarketAverages) string {
+	value := strings.TrimSpace(m.fieldsMap["investment_return_rate"].Input.Value())
+	for _, benchmark := range marketBenchmarks(avg) {
+		if benchmark.name == m.benchmark && value == fmt.Sprintf("%.1f", benchmark.rate) {
+			return fmt.Sprintf("Filled with the %s 10y average (Ctrl+A: next benchmark)", benchmark.name)
+		}
+	}
+	return "Ctrl+A: fill with a benchmark's 10y average"
+}
+
+// fieldAmount parses the current value of a field, treating empty or invalid input as 0
+func (m FormModel) fieldAmount(key string) float64 {
+	field, ok := m.fieldsMap[key]
+	if !ok {
+		return 0
+	}
+	value, err := parseAmount(field.Input.Value())
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// fieldValue returns the trimmed current value of a field, or "" if there is no such field
+func (m FormModel) fieldValue(key string) string {
+	field, ok := m.fieldsMap[key]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(field.Input.Value())
+}
+
+// loanPayments returns the monthly loan payment from the current field values, with the same
+// compounding and payment frequency as the calculation, and the payment once the rate reverts
+// to the SVR (0 without one). Both are 0 until the loan is complete.
+func (m FormModel) loanPayments() (payment, svrPayment float64) {
+	loanAmount := m.fieldAmount("loan_amount")
+	loanMonths, err := parseDuration(m.fieldValue("loan_term"))
+	if loanAmount <= 0 || err != nil || loanMonths <= 0 {
+		return 0, 0
+	}
+
+	semiAnnual := false
+	switch strings.ToLower(m.fieldValue("compounding")) {
+	case "semiannual", "semi-annual":
+		semiAnnual = true
+	}
+	perYear := 12
+	if frequency, ok := calc.PaymentFrequencies[strings.ToLower(m.fieldValue("payment_frequency"))]; ok {
+		perYear = frequency
+	}
+
+	// Payments are made per period; monthly amounts average them over the year
+	periods := loanMonths * perYear / 12
+	periodRate := calc.PeriodRate(m.fieldAmount("loan_rate"), perYear, semiAnnual)
+	periodPayment := calc.MonthlyPayment(loanAmount, periodRate, periods)
+	payment = periodPayment * float64(perYear) / 12
+
+	// After the fixed period, the balance left is re-amortized at the SVR over the rest of the term
+	if fixedMonths, err := parseDuration(m.fieldValue("fixed_period")); m.fieldValue("svr_rate") != "" && err == nil && fixedMonths > 0 && fixedMonths < loanMonths {
+		fixedPeriods := fixedMonths * perYear / 12
+		balance := loanAmount
+		for i := 0; i < fixedPeriods; i++ {
+			balance -= periodPayment - balance*periodRate
+		}
+		svrRate := calc.PeriodRate(m.fieldAmount("svr_rate"), perYear, semiAnnual)
+		svrPayment = calc.MonthlyPayment(balance, svrRate, periods-fixedPeriods) * float64(perYear) / 12
+	}
+	return payment, svrPayment
+}
+
+// renderCostSummary renders the total monthly buying and renting costs for the values entered so far
+func (m FormModel) renderCostSummary(selectedScenario string) string {
+	// Loan payment is based on the original loan in both scenarios: for SELL vs KEEP,
+	// re-amortizing the remaining balance over the remaining term yields the same payment.
+	// A recast changes it later and isn't shown; the SVR payment is shown alongside.
+	loanPayment, svrPayment := m.loanPayments()
+	otherCosts := calc.MonthlyRecurringExpenses(
+		m.fieldAmount("annual_insurance"), m.fieldAmount("annual_taxes"), m.fieldAmount("monthly_expenses")+m.fieldAmount("replacement_reserve")+m.fieldAmount("buy_utilities"))
+	otherCosts += (m.fieldAmount("ground_rent") + m.fieldAmount("service_charge") + m.fieldAmount("council_tax")) / 12
+
+	// Value-based property tax on today's value
+	homeValue := m.fieldAmount("purchase_price")
+	if selectedScenario == "sell_vs_keep" {
+		homeValue = m.fieldAmount("current_market_value")
+	}
+	otherCosts += homeValue * m.fieldAmount("property_tax_rate") / 100 / 12
+	buyingCost := formatCurrency(loanPayment + otherCosts)
+	if svrPayment > 0 {
+		buyingCost += fmt.Sprintf(" (%s on SVR)", formatCurrency(svrPayment+otherCosts))
+	}
+	rentingCost := calc.MonthlyRentingCost(
+		m.fieldAmount("monthly_rent")+m.fieldAmount("rent_utilities"), m.fieldAmount("annual_rent_costs"), m.fieldAmount("other_annual_costs"), m.fieldAmount("renters_insurance"))
+
+	labelStyle := lipgloss.NewStyle().Foreground(activeTheme.Accent)
+	if selectedScenario == "sell_vs_keep" {
+		summary := fmt.Sprintf("  %s %s", labelStyle.Render("Monthly Cost  Keeping:"), buyingCost)
+		if rentingField, ok := m.fieldsMap["include_renting_sell"]; ok && rentingField.Toggled {
+			summary += fmt.Sprintf("  %s %s", labelStyle.Render("Renting:"), formatCurrency(rentingCost))
+		}
+		return summary
+	}
+
+	return fmt.Sprintf("  %s %s  %s %s",
+		labelStyle.Render("Monthly Cost  Buying:"), buyingCost,
+		labelStyle.Render("Renting:"), formatCurrency(rentingCost))
+}
+
 // handleSaveDialog handles key presses in save dialog mode
 func (m FormModel) handleSaveDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -652,7 +920,7 @@ func (m FormModel) renderSaveDialog() string {
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(MonokaiPink).
+		BorderForeground(activeTheme.Primary).
 		Padding(1, 2).
 		Width(50)
 
@@ -673,7 +941,7 @@ func (m FormModel) renderLoadDialog() string {
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(MonokaiPink).
+		BorderForeground(activeTheme.Primary).
 		Padding(1, 2).
 		Width(50)
 
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewFormModelMarketData(t *testing.T) {
+	md := &MarketData{
+		VOO: map[string]float64{"2022": -18, "2023": 26, "2024": 25},
+		QQQ: map[string]float64{"2022": -33, "2023": 55, "2024": 25},
+		VTI: map[string]float64{"2022": -19, "2023": 26, "2024": 24},
+		BND: map[string]float64{"2022": -13, "2023": 6, "2024": 1},
+	}
+	m := NewFormModel(map[string]string{"investment_return_rate": "7"}, md)
+	if m.marketData != md {
+		t.Fatalf("marketData = %p, want %p", m.marketData, md)
+	}
+
+	// Ctrl+A on the investment return fills in the stored data's VOO average
+	for i, field := range m.fields {
+		if field.Key == "investment_return_rate" {
+			m.currentField = i
+		}
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	m = updated.(FormModel)
+
+	want := fmt.Sprintf("%.1f", calculateMarketAverages(md).VOO)
+	if got := m.fieldsMap["investment_return_rate"].Input.Value(); got != want {
+		t.Errorf("investment_return_rate after Ctrl+A = %q, want %q", got, want)
+	}
+	if m.benchmark != "VOO" {
+		t.Errorf("benchmark = %q, want \"VOO\"", m.benchmark)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"calculator/calc"
+)
+
+// historyEntry is one run's key inputs and outcome, appended to the history log
+type historyEntry struct {
+	Time                  string    `json:"time"`
+	Scenario              string    `json:"scenario"` // "buy_vs_rent" or "sell_vs_keep"
+	PurchasePrice         float64   `json:"purchase_price"`
+	CurrentMarketValue    float64   `json:"current_market_value,omitempty"`
+	LoanAmount            float64   `json:"loan_amount"`
+	LoanRate              float64   `json:"loan_rate"`
+	MonthlyRent           float64   `json:"monthly_rent"`
+	AppreciationRates     []float64 `json:"appreciation_rates"`
+	AppreciationInDollars bool      `json:"appreciation_in_dollars,omitempty"`
+	InvestmentReturnRates []float64 `json:"investment_return_rates"`
+	InflationRates        []float64 `json:"inflation_rates"`
+	HorizonMonths         int       `json:"horizon_months"`
+	// Net worth of each side at 10 years and at the horizon: BUY and RENT, or KEEP and SELL
+	Owning10Y      float64 `json:"owning_10y"`
+	Alternative10Y float64 `json:"alternative_10y"`
+	Owning         float64 `json:"owning"`
+	Alternative    float64 `json:"alternative"`
+}
+
+// historyFile returns the path of the run history log (~/.rentobuy/history.jsonl)
+func historyFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %v", err)
+	}
+	return filepath.Join(home, ".rentobuy", "history.jsonl"), nil
+}
+
+// logRun appends the run's key inputs and outcome to the history log. Failures are reported
+// on stderr so they don't get in the way of the results.
+func logRun(scenario *calc.Scenario, isSellVsKeep bool) {
+	horizon := projectionHorizon()
+	entry := historyEntry{
+		Time:                  time.Now().Format(time.RFC3339),
+		Scenario:              "buy_vs_rent",
+		PurchasePrice:         scenario.PurchasePrice,
+		CurrentMarketValue:    scenario.CurrentMarketValue,
+		LoanAmount:            scenario.LoanAmount,
+		LoanRate:              scenario.AnnualRate,
+		MonthlyRent:           scenario.MonthlyRent,
+		AppreciationRates:     scenario.AppreciationRates,
+		AppreciationInDollars: scenario.AppreciationInDollars,
+		InvestmentReturnRates: scenario.InvestmentReturnRates,
+		InflationRates:        scenario.InflationRates,
+		HorizonMonths:         horizon,
+	}
+	if isSellVsKeep {
+		entry.Scenario = "sell_vs_keep"
+		entry.Owning10Y, entry.Alternative10Y = scenario.KeepNetWorthAt(120), scenario.SellNetWorthAt(120)
+		entry.Owning, entry.Alternative = scenario.KeepNetWorthAt(horizon), scenario.SellNetWorthAt(horizon)
+	} else {
+		entry.Owning10Y, entry.Alternative10Y = scenario.NetWorthAt(120).NetWorth, scenario.RentingNetWorthAt(120).NetWorth
+		entry.Owning, entry.Alternative = scenario.NetWorthAt(horizon).NetWorth, scenario.RentingNetWorthAt(horizon).NetWorth
+	}
+
+	if err := appendHistory(entry); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not save run history:", err)
+	}
+}
+
+// appendHistory writes one entry as a JSON line at the end of the history log
+func appendHistory(entry historyEntry) error {
+	path, err := historyFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// displayHistory prints the logged runs, oldest first
+func displayHistory() error {
+	path, err := historyFile()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No run history yet:", path)
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	rows := [][]string{
+		{"Date", "Scenario", "Price", "Loan Rate", "Rent", "Appreciation", "Return", "10Y Own/Alt", "Horizon", "Own", "Alt"},
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip lines from incompatible versions
+		}
+
+		date := entry.Time
+		if t, err := time.Parse(time.RFC3339, entry.Time); err == nil {
+			date = t.Format("2006-01-02 15:04")
+		}
+		appreciation := formatRateList(entry.AppreciationRates)
+		if entry.AppreciationInDollars {
+			appreciation = formatCurrencyList(entry.AppreciationRates)
+		}
+		scenario, price := "BUY/RENT", entry.PurchasePrice
+		if entry.Scenario == "sell_vs_keep" {
+			scenario, price = "KEEP/SELL", entry.CurrentMarketValue
+		}
+
+		rows = append(rows, []string{
+			date,
+			scenario,
+			formatCurrency(price),
+			fmt.Sprintf("%.2f%%", entry.LoanRate),
+			formatCurrency(entry.MonthlyRent),
+			appreciation,
+			formatRateList(entry.InvestmentReturnRates),
+			formatCurrency(entry.Owning10Y) + " / " + formatCurrency(entry.Alternative10Y),
+			formatHorizon(entry.HorizonMonths),
+			formatCurrency(entry.Owning),
+			formatCurrency(entry.Alternative),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	notes := fmt.Sprintf("Note: Net worth of owning (BUY or KEEP) and of the alternative (RENT or SELL). Logged to %s.", path)
+	displayTable("RUN HISTORY", rows, notes, false, nil)
+	return nil
+}
+
+// formatRateList formats a rate schedule compactly for the history table (e.g., "10,5,3%")
+func formatRateList(rates []float64) string {
+	strs := make([]string, len(rates))
+	for i, rate := range rates {
+		strs[i] = strconv.FormatFloat(rate, 'f', -1, 64)
+	}
+	return strings.Join(strs, ",") + "%"
+}
+
+// formatCurrencyList formats a dollar schedule compactly for the history table
+func formatCurrencyList(amounts []float64) string {
+	strs := make([]string, len(amounts))
+	for i, amount := range amounts {
+		strs[i] = formatCurrency(amount)
+	}
+	return strings.Join(strs, ",")
+}
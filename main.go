@@ -5,71 +5,32 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"golang.org/x/term"
+
+	"calculator/calc"
 )
 
 var reader = bufio.NewReader(os.Stdin)
-var savedDefaults map[string]string
-var currentInputs map[string]string
 var useDefaults bool
 var fullNumbers bool
-
-// Global arrays for monthly costs
-var monthlyBuyingCosts []float64
-var monthlyRentingCosts []float64
-var remainingLoanBalance []float64
-var cumulativePrincipalPaid []float64
-var cumulativeInterestPaid []float64
-var appreciationRates []float64 // Annual appreciation rates
-var taxFreeLimits []float64     // Tax-free capital gains limits by year
-
-// Global arrays for KEEP scenario investment tracking
-var monthlyKeepInvestmentValue []float64 // Investment value at each month
-var monthlyKeepRealCosts []float64       // Cumulative real out-of-pocket costs at each month
-var monthlyKeepNetPosition []float64     // Net position (investment - real costs) at each month
-
-// Config holds all input parameters
-type Config struct {
-	// Economic
-	inflationRate float64
-	include30Year float64
-
-	// Buying/Asset
-	purchasePrice      float64 // Original purchase price (for capital gains)
-	currentMarketValue float64 // Current value (for SELL vs KEEP)
-	downpayment        float64
-	loanAmount         float64
-	annualRate         float64
-	totalMonths        int
-	monthlyRate        float64
-	monthlyLoanPayment float64
-	annualInsurance    float64
-	annualTaxes        float64
-	monthlyExpenses    float64
-	totalMonthlyBuyingCost float64
-
-	// Renting
-	rentDeposit            float64
-	monthlyRent            float64
-	annualRentCosts        float64
-	otherAnnualCosts       float64
-	investmentReturnRate   float64
-	totalMonthlyRentingCost float64
-
-	// Selling
-	includeSelling  float64
-	agentCommission float64
-	stagingCosts    float64
-	capitalGainsTax float64
-}
-
-var config Config
+var customPeriods []int // Projection periods in months from --periods (empty = default set)
+var sortByDifference bool
+var annualPeriods bool
+var include30Year bool // Show 15/20/30-year projections
+var discountRate float64 // Annual discount rate (%) for the NPV comparison (0 = not shown)
+var sdltBands string      // Stamp duty bands from --sdlt-bands, used when stamp_duty is empty
+var dryRun bool           // Validate and show the inputs, then exit before the projections
 
 const inputsFile = ".rentobuy_inputs.json"
 
@@ -78,14 +39,139 @@ func main() {
 	// fmt.Print("\033[H\033[2J")
 
 	// Parse command line flags
-	flag.BoolVar(&useDefaults, "defaults", false, "Use all previously saved default values without prompting")
+	flag.BoolVar(&useDefaults, "defaults", false, "Use all previously saved default values without prompting. Inputs can also be set as RENTOBUY_<KEY> environment variables (e.g., RENTOBUY_PURCHASE_PRICE), which override the saved values; --stdin, --import, and key=value arguments after the flags (e.g., purchase_price=450k, which also skip the form) override both")
+	flag.BoolVar(&dryRun, "dry-run", false, "Validate the inputs and show them, then exit without fetching market data or projecting; exits with status 1 if the inputs are invalid")
 	flag.BoolVar(&fullNumbers, "full-numbers", false, "Display full numbers instead of compact K/M format")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colored output (also honors the NO_COLOR env var)")
+	themeName := flag.String("theme", "monokai", "Color theme: monokai, solarized, or mono")
+	flag.BoolVar(&sortByDifference, "sort", false, "Sort BUY vs RENT comparison rows by the size of the RENT - BUY difference")
+	flag.Float64Var(&discountRate, "discount-rate", 0, "Annual discount rate (%) for a net present value comparison of BUY vs RENT cash flows")
+	flag.StringVar(&sdltBands, "sdlt-bands", "", "Stamp duty bands as limit:rate%, last rate for the rest, to compute stamp duty from the purchase price (e.g., 125K:0,250K:2,925K:5,1.5M:10,12)")
+	flag.BoolVar(&annualPeriods, "annual", false, "Show a row for every year up to the projection horizon in all tables")
+	periodsFlag := flag.String("periods", "", "Comma-separated projection periods replacing the default set (e.g., 2y,7y,12y)")
+	flag.DurationVar(&httpTimeout, "http-timeout", httpTimeout, "Timeout for each market data request (e.g., 60s); HTTP_PROXY/HTTPS_PROXY are honored")
+	flag.StringVar(&userAgent, "user-agent", userAgent, "User-Agent header sent when fetching market data")
+	flag.StringVar(&marketDataInterval, "market-interval", marketDataInterval, "Yahoo Finance bar size used when refreshing market data: "+strings.Join(validIntervals, ", ")+" (1wk and 1mo imply --close-to-close)")
+	flag.BoolVar(&priceReturnOnly, "price-return", false, "Use price-only market returns (no dividends) instead of total returns; refetches market data when switched")
+	flag.BoolVar(&closeToCloseReturns, "close-to-close", false, "When refreshing market data, measure each year's return from the prior year's last close instead of the year's first close (implied by --market-interval 1wk or 1mo); refetches market data when switched")
+	readStdin := flag.Bool("stdin", false, "Read inputs as JSON (same shape as "+inputsFile+") from standard input")
+	solveFlag := flag.String("solve", "", "Solve for the breakeven value of an input instead of showing the tables: "+strings.Join(solveTargets, ", "))
+	compareTermsFlag := flag.String("compare-terms", "", "Compare buying with each comma-separated loan term instead of showing the tables (e.g., 15y,30y)")
+	timelinePath := flag.String("timeline", "", "Write a month-by-month BUY vs RENT CSV (costs, loan balance, asset value, net worth) to this path")
+	chartPath := flag.String("chart", "", "Write a PNG chart of BUY vs RENT net worth by month to this path (needs -tags chart)")
+	flag.IntVar(&monteCarloTrials, "montecarlo", 0, "Run this many Monte Carlo trials with randomly drawn yearly returns and appreciation, and show the spread of outcomes")
+	flag.Uint64Var(&monteCarloSeed, "seed", monteCarloSeed, "Random seed for --montecarlo, so runs are reproducible")
+	flag.Float64Var(&returnStdev, "return-stdev", returnStdev, "Yearly standard deviation (%) of the investment return in --montecarlo")
+	flag.Float64Var(&appreciationStdev, "appreciation-stdev", appreciationStdev, "Yearly standard deviation (%) of the appreciation rate in --montecarlo")
+	flag.StringVar(&monteCarloDraw, "mc-draw", monteCarloDraw, "How --montecarlo draws yearly investment returns: gaussian, or bootstrap (resample historical market years)")
+	flag.Float64Var(&monteCarloCorrelation, "correlation", monteCarloCorrelation, "Correlation (-1 to 1) between each year's investment return and appreciation in --montecarlo, so both can crash together (default 0: independent, as there's no home price history to measure it from)")
+	stressFlag := flag.String("stress", "", "Replay a historical crash before the input rates resume: "+strings.Join(stressNames(), ", "))
+	flag.IntVar(&debugMonth, "debug", 0, "Log the intermediate calculations for this month (e.g., 60) to stderr")
+	shareFlag := flag.Bool("share", false, "Print a compact, URL-safe token encoding these inputs, to share the exact scenario")
+	importToken := flag.String("import", "", "Run the scenario encoded in a --share token instead of showing the form")
+	vsLast := flag.Bool("vs-last", false, "Show which inputs changed since the previous run and how that moved the outcome")
+	showHistory := flag.Bool("history", false, "Print the log of previous runs' key inputs and outcomes (~/.rentobuy/history.jsonl), then exit")
+	showVersion := flag.Bool("version", false, "Print the version, git commit, and build date, then exit")
+	horizonFlag := flag.String("horizon", "", "Horizon used by --solve and --compare-terms (e.g., 7y, 30y; default is the furthest projection period)")
 	flag.Parse()
 
-	// Update market data (blocking to ensure we have it for display)
-	marketData, err := updateMarketData()
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	if !slices.Contains(validIntervals, marketDataInterval) {
+		fmt.Printf("Error: invalid --market-interval %q (expected one of: %s)\n", marketDataInterval, strings.Join(validIntervals, ", "))
+		return
+	}
+
+	applyColorMode()
+	if err := setTheme(*themeName); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	if *showHistory {
+		if err := displayHistory(); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
+
+	if *periodsFlag != "" {
+		periods, err := parsePeriods(*periodsFlag)
+		if err != nil {
+			fmt.Println("Error: invalid --periods:", err)
+			return
+		}
+		customPeriods = periods
+	}
+
+	var compareTerms []int
+	if *compareTermsFlag != "" {
+		terms, err := parsePeriods(*compareTermsFlag)
+		if err != nil {
+			fmt.Println("Error: invalid --compare-terms:", err)
+			return
+		}
+		compareTerms = terms
+	}
+
+	if debugMonth < 0 {
+		fmt.Println("Error: --debug must be a positive month number")
+		return
+	}
+
+	// Positional key=value arguments override individual inputs and skip the form
+	argValues, err := argInputs(flag.Args())
 	if err != nil {
-		fmt.Println("Warning: Could not fetch market data:", err)
+		inputError("Error: invalid input argument:", err)
+		return
+	}
+	if monteCarloTrials < 0 || returnStdev < 0 || appreciationStdev < 0 {
+		fmt.Println("Error: --montecarlo, --return-stdev and --appreciation-stdev can't be negative")
+		return
+	}
+	if monteCarloCorrelation < -1 || monteCarloCorrelation > 1 {
+		fmt.Println("Error: --correlation must be between -1 and 1")
+		return
+	}
+	if !slices.Contains(monteCarloDraws, monteCarloDraw) {
+		fmt.Printf("Error: invalid --mc-draw %q (expected one of: %s)\n", monteCarloDraw, strings.Join(monteCarloDraws, ", "))
+		return
+	}
+	if debugMonth > 0 {
+		enableDebug()
+	}
+
+	horizonMonths := 0
+	if _, ok := stressScenarios[*stressFlag]; *stressFlag != "" && !ok {
+		fmt.Printf("Error: invalid --stress %q (expected one of: %s)\n", *stressFlag, strings.Join(stressNames(), ", "))
+		return
+	}
+	if *solveFlag != "" && !validSolveTarget(*solveFlag) {
+		fmt.Printf("Error: invalid --solve %q (expected one of: %s)\n", *solveFlag, strings.Join(solveTargets, ", "))
+		return
+	}
+	if *horizonFlag != "" {
+		months, err := parseDuration(*horizonFlag)
+		if err != nil || months <= 0 {
+			fmt.Println("Error: invalid --horizon:", *horizonFlag)
+			return
+		}
+		horizonMonths = months
+	}
+
+	// Update market data (blocking to ensure we have it for display); a dry run skips it
+	var marketData *MarketData
+	if !dryRun {
+		marketData, err = updateMarketData()
+		if err != nil {
+			fmt.Println("Warning: Could not fetch market data:", err)
+			marketData = nil
+		}
+	}
+	if marketData == nil {
 		// Continue anyway with empty market data
 		marketData = &MarketData{
 			VOO: make(map[string]float64),
@@ -95,320 +181,718 @@ func main() {
 		}
 	}
 
-	// Load previous inputs (for --defaults flag backward compatibility)
-	savedDefaults = loadInputs()
-	currentInputs = make(map[string]string)
+	var values map[string]string
+	lastRun := loadLastRun() // Settings of the previous run, for --vs-last
 
-	// If not using defaults, show interactive form
-	if !useDefaults {
-		// Show interactive form with last saved defaults
-		values, err := RunInteractiveForm(savedDefaults, marketData)
+	if *importToken != "" {
+		// Non-interactive: take inputs from a shared token
+		values, err = decodeShareToken(*importToken)
 		if err != nil {
-			fmt.Println("Form cancelled or error:", err)
+			inputError("Error: invalid --import token:", err)
 			return
 		}
-		currentInputs = values
-
-		// Save the inputs for next time (backward compatibility)
-		saveInputs(currentInputs)
-	} else {
-		// Check if we have defaults when --defaults flag is used
-		if len(savedDefaults) == 0 {
-			fmt.Println("Error: --defaults flag used but no saved defaults found. Run without the flag first.")
+		values = withInputs(values, argValues)
+	} else if *readStdin {
+		// Non-interactive: take inputs from stdin, skipping the form and saved defaults
+		values, err = readInputs(os.Stdin)
+		if err != nil {
+			inputError("Error: invalid --stdin inputs:", err)
 			return
 		}
-		// Use saved defaults
-		currentInputs = savedDefaults
+		values = withInputs(values, argValues)
+	} else {
+		// Load previous inputs (for --defaults flag backward compatibility), with any
+		// RENTOBUY_<KEY> environment variables on top. Precedence: flags (--stdin,
+		// --import, key=value arguments) > environment > saved file.
+		savedDefaults := withInputs(loadInputs(), envInputs())
+
+		// If not using defaults, show interactive form
+		if !useDefaults && len(argValues) == 0 {
+			// Show interactive form with last saved defaults
+			values, err = RunInteractiveForm(savedDefaults, marketData)
+			if err != nil {
+				fmt.Println("Form cancelled or error:", err)
+				return
+			}
+
+			// Save the inputs for next time (backward compatibility)
+			saveInputs(values)
+		} else {
+			// Check if we have defaults when --defaults flag is used
+			if len(savedDefaults) == 0 && len(argValues) == 0 {
+				inputError("Error: --defaults flag used but no saved defaults or " + envPrefix + "* environment variables found. Run without the flag first.")
+				return
+			}
+			// Use saved defaults, with any key=value arguments on top
+			values = withInputs(savedDefaults, argValues)
+		}
 	}
 
 	// Determine which scenario is selected
-	scenarioSellVsKeep, _ := getFloatValue("scenario_sell_vs_keep")
+	scenarioSellVsKeep, _ := getFloatValue(values, "scenario_sell_vs_keep")
 	isSellVsKeep := scenarioSellVsKeep > 0
 
+	// Extended projections are a display option; default to 10-year projections only
+	extendedProjections, _ := getFloatValue(values, "include_30year")
+	include30Year = extendedProjections > 0
+
 	// Parse configuration for the selected scenario
-	err = parseConfig(isSellVsKeep)
+	inputs, err := parseConfig(values, isSellVsKeep)
 	if err != nil {
-		fmt.Println("Error parsing inputs:", err)
+		inputError("Error parsing inputs:", err)
+		return
+	}
+	if *stressFlag != "" {
+		inputs, err = applyStress(inputs, *stressFlag)
+		if err != nil {
+			inputError("Error:", err)
+			return
+		}
+		fmt.Printf("\nStress test applied: %s.\n", stressScenarios[*stressFlag].Description)
+	}
+	scenario := calc.NewScenario(inputs)
+
+	if dryRun {
+		if isSellVsKeep {
+			displayInputParametersSellVsKeep(scenario, marketData)
+		} else {
+			displayInputParameters(scenario, marketData)
+		}
+		fmt.Println()
+		fmt.Println("Inputs are valid (dry run: no projections).")
+		return
+	}
+
+	if horizonMonths == 0 {
+		horizonMonths = projectionHorizon()
+	}
+	if *solveFlag != "" {
+		runSolver(*solveFlag, inputs, horizonMonths, isSellVsKeep)
 		return
 	}
+	if len(compareTerms) > 0 {
+		runTermComparison(inputs, compareTerms, horizonMonths, isSellVsKeep)
+		return
+	}
+
+	if *timelinePath != "" {
+		if isSellVsKeep {
+			fmt.Println("Warning: --timeline applies to the BUY vs RENT scenario only")
+		} else if err := writeTimeline(*timelinePath, scenario); err != nil {
+			fmt.Println("Warning:", err)
+		}
+	}
+
+	if *chartPath != "" {
+		if isSellVsKeep {
+			fmt.Println("Warning: --chart applies to the BUY vs RENT scenario only")
+		} else if err := writeChart(*chartPath, scenario); err != nil {
+			fmt.Println("Warning:", err)
+		}
+	}
+
+	dumpDebugMonth(scenario, debugMonth, isSellVsKeep)
 
 	// Route to the appropriate scenario
 	if isSellVsKeep {
-		runSellVsKeepScenario(marketData)
+		runSellVsKeepScenario(scenario, marketData)
 	} else {
-		runBuyVsRentScenario(marketData)
+		runBuyVsRentScenario(scenario, marketData)
+	}
+	run := runSettings{Inputs: values, Flags: outcomeFlags(*periodsFlag, *stressFlag)}
+	saveLastRun(run)
+
+	if *vsLast {
+		displayVsLast(run, lastRun, scenario, isSellVsKeep)
+	}
+
+	if *shareFlag {
+		token, err := encodeShareToken(values)
+		if err != nil {
+			fmt.Println("Error: could not create a share token:", err)
+			return
+		}
+		fmt.Println()
+		fmt.Println("Share these inputs with: rentobuy --import " + token)
 	}
 }
 
-// parseConfig parses all input fields into the global config struct
-func parseConfig(isSellVsKeep bool) error {
+// inputError prints an error about the inputs; a dry run also exits with a non-zero status
+// so scripts can catch it
+func inputError(a ...any) {
+	fmt.Println(a...)
+	if dryRun {
+		os.Exit(1)
+	}
+}
+
+// parseConfig parses all input fields into the inputs of a calc scenario
+func parseConfig(values map[string]string, isSellVsKeep bool) (calc.Inputs, error) {
+	var inputs calc.Inputs
 	var err error
 
 	// === COMMON FIELDS (always parsed) ===
 
 	// Economic assumptions
-	config.inflationRate, err = getFloatValue("inflation_rate")
+	// Inflation rate, optionally a comma-separated schedule by year
+	inflationRateStr := values["inflation_rate"]
+	if strings.TrimSpace(inflationRateStr) == "" {
+		return inputs, fmt.Errorf("invalid inflation rate: value is required")
+	}
+	inputs.InflationRates, err = parseAppreciationRates(inflationRateStr)
 	if err != nil {
-		return fmt.Errorf("invalid inflation rate: %v", err)
+		return inputs, fmt.Errorf("invalid inflation rate: %v", err)
 	}
 
-	config.include30Year, err = getFloatValue("include_30year")
+	// Ongoing costs (shared across scenarios)
+	inputs.AnnualInsurance, err = getFloatValue(values, "annual_insurance")
 	if err != nil {
-		config.include30Year = 0 // Default to 10-year projections only
+		return inputs, fmt.Errorf("invalid annual insurance: %v", err)
 	}
 
-	// Ongoing costs (shared across scenarios)
-	config.annualInsurance, err = getFloatValue("annual_insurance")
+	// Optional property tax as a percentage of value (empty = included in annual insurance)
+	inputs.PropertyTaxRate, err = getFloatValue(values, "property_tax_rate")
+	if err != nil || inputs.PropertyTaxRate < 0 {
+		inputs.PropertyTaxRate = 0
+	}
+
+	// Optional property tax reassessment cap (empty = tax grows with inflation)
+	if strings.TrimSpace(values["tax_reassessment_cap"]) != "" {
+		inputs.TaxReassessmentCap, err = getFloatValue(values, "tax_reassessment_cap")
+		if err != nil {
+			return inputs, fmt.Errorf("invalid tax reassessment cap: %v", err)
+		}
+		inputs.CapPropertyTax = true
+	}
+
+	inputs.AnnualTaxes, err = getFloatValue(values, "annual_taxes")
 	if err != nil {
-		return fmt.Errorf("invalid annual insurance: %v", err)
+		return inputs, fmt.Errorf("invalid annual taxes: %v", err)
 	}
 
-	config.annualTaxes, err = getFloatValue("annual_taxes")
+	inputs.MonthlyExpenses, err = getFloatValue(values, "monthly_expenses")
 	if err != nil {
-		return fmt.Errorf("invalid annual taxes: %v", err)
+		return inputs, fmt.Errorf("invalid monthly expenses: %v", err)
 	}
 
-	config.monthlyExpenses, err = getFloatValue("monthly_expenses")
+	inputs.ReplacementReserve, err = getFloatValue(values, "replacement_reserve")
 	if err != nil {
-		return fmt.Errorf("invalid monthly expenses: %v", err)
+		inputs.ReplacementReserve = 0
+	}
+
+	// Utilities on each side (default 0)
+	inputs.BuyUtilities, err = getFloatValue(values, "buy_utilities")
+	if err != nil {
+		inputs.BuyUtilities = 0
+	}
+	inputs.RentUtilities, err = getFloatValue(values, "rent_utilities")
+	if err != nil {
+		inputs.RentUtilities = 0
+	}
+
+	// Leasehold ground rent and service charge (annual, default 0)
+	inputs.GroundRent, err = getFloatValue(values, "ground_rent")
+	if err != nil || inputs.GroundRent < 0 {
+		return inputs, fmt.Errorf("invalid ground rent - must be an annual amount of zero or more")
+	}
+	inputs.GroundRentIncrease, err = getFloatValue(values, "ground_rent_increase")
+	if err != nil {
+		return inputs, fmt.Errorf("invalid ground rent increase: %v", err)
+	}
+	inputs.ServiceCharge, err = getFloatValue(values, "service_charge")
+	if err != nil || inputs.ServiceCharge < 0 {
+		return inputs, fmt.Errorf("invalid service charge - must be an annual amount of zero or more")
+	}
+	if strings.TrimSpace(values["service_charge_increase"]) != "" {
+		inputs.ServiceChargeIncrease, err = getFloatValue(values, "service_charge_increase")
+		if err != nil {
+			return inputs, fmt.Errorf("invalid service charge increase: %v", err)
+		}
+		inputs.ServiceChargeOwnRate = true
+	}
+
+	// UK council tax (annual, default 0), growing with inflation unless it has its own rate
+	inputs.CouncilTax, err = getFloatValue(values, "council_tax")
+	if err != nil || inputs.CouncilTax < 0 {
+		return inputs, fmt.Errorf("invalid council tax - must be an annual amount of zero or more")
+	}
+	if strings.TrimSpace(values["council_tax_increase"]) != "" {
+		inputs.CouncilTaxIncrease, err = getFloatValue(values, "council_tax_increase")
+		if err != nil {
+			return inputs, fmt.Errorf("invalid council tax increase: %v", err)
+		}
+		inputs.CouncilTaxOwnRate = true
 	}
 
 	// Appreciation rate (shared)
-	appreciationRateStr := currentInputs["appreciation_rate"]
-	appreciationRates, err = parseAppreciationRates(appreciationRateStr)
+	appreciationRateStr := values["appreciation_rate"]
+	inputs.AppreciationRates, err = parseAppreciationRates(appreciationRateStr)
 	if err != nil {
-		return fmt.Errorf("invalid appreciation rate: %v", err)
+		return inputs, fmt.Errorf("invalid appreciation rate: %v", err)
 	}
+	// Capital improvements by year (a single value is a one-time improvement in year 1)
+	inputs.CapitalImprovements, err = parseAppreciationRates(values["capital_improvements"])
+	if err != nil {
+		return inputs, fmt.Errorf("invalid capital improvements: %v", err)
+	}
+
+	appreciationMode, _ := getFloatValue(values, "appreciation_mode")
+	inputs.AppreciationInDollars = appreciationMode > 0
 
 	// Rental fields (always parsed)
-	config.rentDeposit, err = getFloatValue("rent_deposit")
+	inputs.RentDeposit, err = getFloatValue(values, "rent_deposit")
 	if err != nil {
-		return fmt.Errorf("invalid rental deposit: %v", err)
+		return inputs, fmt.Errorf("invalid rental deposit: %v", err)
 	}
+	depositEarnsReturns, _ := getFloatValue(values, "deposit_earns_returns")
+	inputs.DepositEarnsReturns = depositEarnsReturns > 0
 
-	config.monthlyRent, err = getFloatValue("monthly_rent")
+	inputs.MonthlyRent, err = getFloatValue(values, "monthly_rent")
 	if err != nil {
-		return fmt.Errorf("invalid monthly rent: %v", err)
+		return inputs, fmt.Errorf("invalid monthly rent: %v", err)
 	}
 
-	config.annualRentCosts, err = getFloatValue("annual_rent_costs")
+	inputs.AnnualRentCosts, err = getFloatValue(values, "annual_rent_costs")
 	if err != nil {
-		return fmt.Errorf("invalid annual rent costs: %v", err)
+		return inputs, fmt.Errorf("invalid annual rent costs: %v", err)
 	}
 
-	config.otherAnnualCosts, err = getFloatValue("other_annual_costs")
+	inputs.OtherAnnualCosts, err = getFloatValue(values, "other_annual_costs")
 	if err != nil {
-		return fmt.Errorf("invalid other annual costs: %v", err)
+		return inputs, fmt.Errorf("invalid other annual costs: %v", err)
 	}
 
-	config.investmentReturnRate, err = getFloatValue("investment_return_rate")
+	inputs.RentersInsurance, err = getFloatValue(values, "renters_insurance")
 	if err != nil {
-		return fmt.Errorf("invalid investment return rate: %v", err)
+		inputs.RentersInsurance = 0
 	}
 
-	// Selling parameters (always parsed - used differently in each scenario)
-	config.includeSelling, err = getFloatValue("include_selling")
+	// Periodic moving costs (optional)
+	inputs.MoveCost, err = getFloatValue(values, "move_cost")
 	if err != nil {
-		config.includeSelling = 0
+		inputs.MoveCost = 0
+	}
+	moveEveryYears, err := getFloatValue(values, "move_every_years")
+	if err != nil || moveEveryYears < 0 {
+		moveEveryYears = 0
 	}
+	inputs.MoveEveryMonths = int(math.Round(moveEveryYears * 12))
 
-	config.agentCommission, err = getFloatValue("agent_commission")
+	inputs.LeaseBreakPenalty, err = getFloatValue(values, "lease_break_penalty")
 	if err != nil {
-		config.agentCommission = 0
+		inputs.LeaseBreakPenalty = 0
 	}
 
-	config.stagingCosts, err = getFloatValue("staging_costs")
+	// Rent concession at the start of the lease (default 0)
+	inputs.RentFreeMonths, err = getIntValue(values, "rent_free_months", strconv.Atoi)
+	if err != nil || inputs.RentFreeMonths < 0 {
+		inputs.RentFreeMonths = 0
+	}
+
+	// Optional rent-control cap on yearly rent increases (empty = rent grows with inflation)
+	if strings.TrimSpace(values["rent_increase_cap"]) != "" {
+		inputs.RentIncreaseCap, err = getFloatValue(values, "rent_increase_cap")
+		if err != nil {
+			return inputs, fmt.Errorf("invalid rent increase cap: %v", err)
+		}
+		inputs.CapRentIncrease = true
+	}
+
+	// Months of rent prepaid at move-in, e.g. first and last month (default 0)
+	inputs.RentUpfrontMonths, err = getIntValue(values, "rent_upfront_months", strconv.Atoi)
+	if err != nil || inputs.RentUpfrontMonths < 0 {
+		inputs.RentUpfrontMonths = 0
+	}
+
+	// Broker fee at move-in, in dollars or as a % of the first year's rent (e.g., 15%)
+	inputs.BrokerFee, err = getFloatValue(values, "broker_fee")
+	if err != nil || inputs.BrokerFee < 0 {
+		return inputs, fmt.Errorf("invalid broker fee - must be a dollar amount or a %% of annual rent")
+	}
+	if strings.HasSuffix(strings.TrimSpace(values["broker_fee"]), "%") {
+		inputs.BrokerFee = inputs.BrokerFee / 100 * inputs.MonthlyRent * 12
+	}
+
+	// Investment return rate, optionally a comma-separated schedule by year
+	investmentReturnRateStr := values["investment_return_rate"]
+	if strings.TrimSpace(investmentReturnRateStr) == "" {
+		return inputs, fmt.Errorf("invalid investment return rate: value is required")
+	}
+	inputs.InvestmentReturnRates, err = parseAppreciationRates(investmentReturnRateStr)
 	if err != nil {
-		config.stagingCosts = 0
+		return inputs, fmt.Errorf("invalid investment return rate: %v", err)
 	}
 
+	// Annual fees / expense ratio on invested money (default 0)
+	inputs.InvestmentFee, err = getFloatValue(values, "investment_fee")
+	if err != nil || inputs.InvestmentFee < 0 {
+		return inputs, fmt.Errorf("invalid investment fee - must be a non-negative %% per year")
+	}
+
+	// Selling parameters (always parsed - used differently in each scenario)
+	includeSelling, _ := getFloatValue(values, "include_selling")
+	inputs.IncludeSelling = includeSelling > 0
+
+	includeRenting, _ := getFloatValue(values, "include_renting_sell")
+	inputs.IncludeRenting = includeRenting > 0
+
+	inputs.AgentCommission, err = getFloatValue(values, "agent_commission")
+	if err != nil {
+		inputs.AgentCommission = 0
+	}
+
+	inputs.StagingCosts, err = getFloatValue(values, "staging_costs")
+	if err != nil {
+		inputs.StagingCosts = 0
+	}
+
+	inputs.TransferTaxPct, err = getFloatValue(values, "transfer_tax_pct")
+	if err != nil || inputs.TransferTaxPct < 0 {
+		inputs.TransferTaxPct = 0
+	}
+	transferTaxOnSale, _ := getFloatValue(values, "transfer_tax_on_sale")
+	inputs.TransferTaxOnSale = transferTaxOnSale > 0
+
 	// Parse tax-free limits as comma-separated values (like appreciation rates)
-	taxFreeLimitStr := currentInputs["tax_free_limit"]
-	taxFreeLimits, err = parseAppreciationRates(taxFreeLimitStr)
+	taxFreeLimitStr := values["tax_free_limit"]
+	inputs.TaxFreeLimits, err = parseAppreciationRates(taxFreeLimitStr)
 	if err != nil {
-		taxFreeLimits = []float64{0}
+		inputs.TaxFreeLimits = []float64{0}
 	}
 
-	config.capitalGainsTax, err = getFloatValue("capital_gains_tax")
+	inputs.CapitalGainsTax, err = getFloatValue(values, "capital_gains_tax")
 	if err != nil {
-		config.capitalGainsTax = 0
+		inputs.CapitalGainsTax = 0
 	}
 
 	// === SCENARIO-SPECIFIC FIELDS ===
 
-	config.purchasePrice, err = getFloatValue("purchase_price")
-	if err != nil || config.purchasePrice == 0 {
-		return fmt.Errorf("invalid purchase price - cannot be zero")
+	inputs.PurchasePrice, err = getFloatValue(values, "purchase_price")
+	if err != nil || inputs.PurchasePrice == 0 {
+		return inputs, fmt.Errorf("invalid purchase price - cannot be zero")
 	}
 
-	config.loanAmount, err = getFloatValue("loan_amount")
+	inputs.LoanAmount, err = getFloatValue(values, "loan_amount")
 	if err != nil {
-		return fmt.Errorf("invalid loan amount: %v", err)
+		return inputs, fmt.Errorf("invalid loan amount: %v", err)
+	}
+
+	// Loan rate compounding: monthly (default, US) or semiannual (Canadian fixed mortgages)
+	switch compounding := strings.ToLower(strings.TrimSpace(values["compounding"])); compounding {
+	case "", "monthly":
+	case "semiannual", "semi-annual":
+		inputs.SemiAnnualCompounding = true
+	default:
+		return inputs, fmt.Errorf("invalid compounding %q - expected monthly or semiannual", compounding)
 	}
 
 	if isSellVsKeep {
 		// SELL vs KEEP specific parsing
-		config.currentMarketValue, err = getFloatValue("current_market_value")
-		if err != nil || config.currentMarketValue == 0 {
-			return fmt.Errorf("invalid current market value - cannot be zero")
+		inputs.CurrentMarketValue, err = getFloatValue(values, "current_market_value")
+		if err != nil || inputs.CurrentMarketValue == 0 {
+			return inputs, fmt.Errorf("invalid current market value - cannot be zero")
 		}
 
 		// For SELL vs KEEP, we calculate remaining loan balance from loan parameters
-		if config.loanAmount > 0 {
+		if inputs.LoanAmount > 0 {
 			// Get loan parameters
-			config.annualRate, err = getFloatValue("loan_rate")
+			inputs.AnnualRate, err = getFloatValue(values, "loan_rate")
 			if err != nil {
-				return fmt.Errorf("invalid loan rate: %v", err)
+				return inputs, fmt.Errorf("invalid loan rate: %v", err)
 			}
 
 			// Get original loan term
-			originalLoanMonths, err := getIntValue("loan_term", parseDuration)
+			originalLoanMonths, err := getIntValue(values, "loan_term", parseDuration)
 			if err != nil {
-				return fmt.Errorf("invalid loan term: %v", err)
+				return inputs, fmt.Errorf("invalid loan term: %v", err)
 			}
 
 			// Get remaining loan term
-			remainingLoanMonths, err := getIntValue("remaining_loan_term", parseDuration)
+			remainingLoanMonths, err := getIntValue(values, "remaining_loan_term", parseDuration)
 			if err != nil {
-				return fmt.Errorf("invalid remaining loan term: %v", err)
+				return inputs, fmt.Errorf("invalid remaining loan term: %v", err)
 			}
 
-			// Calculate monthly rate and payment based on original loan
-			config.monthlyRate = config.annualRate / 100 / 12
-			originalPayment := calculateMonthlyPayment(config.loanAmount, config.monthlyRate, originalLoanMonths)
-
 			// Calculate remaining loan balance by simulating payments up to current point
 			monthsElapsed := originalLoanMonths - remainingLoanMonths
-			remainingBalance := config.loanAmount
-			for i := 0; i < monthsElapsed; i++ {
-				interestPayment := remainingBalance * config.monthlyRate
-				principalPayment := originalPayment - interestPayment
-				remainingBalance -= principalPayment
-			}
+			remainingBalance := calc.RemainingBalance(inputs.LoanAmount, calc.PeriodRate(inputs.AnnualRate, 12, inputs.SemiAnnualCompounding), originalLoanMonths, monthsElapsed)
 
 			// For projections: use remaining term and recalculate payment on remaining balance
-			config.totalMonths = remainingLoanMonths
-			config.monthlyLoanPayment = calculateMonthlyPayment(remainingBalance, config.monthlyRate, remainingLoanMonths)
-			config.downpayment = config.currentMarketValue - remainingBalance // Current equity
-			config.loanAmount = remainingBalance // Update to remaining balance
+			inputs.LoanMonths = remainingLoanMonths
+			inputs.Downpayment = inputs.CurrentMarketValue - remainingBalance // Current equity
+			inputs.LoanAmount = remainingBalance                               // Update to remaining balance
 		} else {
 			// No loan - fully paid off
-			config.annualRate = 0
-			config.totalMonths = 0
-			config.monthlyRate = 0
-			config.monthlyLoanPayment = 0
-			config.downpayment = config.currentMarketValue // Full equity
-			config.loanAmount = 0
+			inputs.Downpayment = inputs.CurrentMarketValue // Full equity
+			inputs.LoanAmount = 0
 		}
 	} else {
 		// BUY vs RENT specific parsing
-		config.downpayment = config.purchasePrice - config.loanAmount
+		inputs.Downpayment = inputs.PurchasePrice - inputs.LoanAmount
+
+		// Optional sell and re-buy after a number of years (both needed)
+		rebuyYear, err := getFloatValue(values, "rebuy_year")
+		if err == nil && rebuyYear > 0 {
+			inputs.RebuyPrice, err = getFloatValue(values, "rebuy_price")
+			if err != nil || inputs.RebuyPrice <= 0 {
+				return inputs, fmt.Errorf("invalid re-buy price - must be greater than zero when selling and re-buying")
+			}
+			inputs.RebuyYear = int(rebuyYear)
+		}
+
+		// Stamp duty: an amount, or bands to compute it from the price (the --sdlt-bands default)
+		stampDuty := strings.TrimSpace(values["stamp_duty"])
+		if stampDuty == "" {
+			stampDuty = sdltBands
+		}
+		if strings.Contains(stampDuty, ":") {
+			bands, err := parseStampDutyBands(stampDuty)
+			if err != nil {
+				return inputs, err
+			}
+			inputs.StampDuty = calc.StampDuty(inputs.PurchasePrice, bands)
+		} else {
+			inputs.StampDuty, err = parseAmount(stampDuty)
+			if err != nil || inputs.StampDuty < 0 {
+				return inputs, fmt.Errorf("invalid stamp duty %q - expected an amount or bands like 125K:0,250K:2,5", stampDuty)
+			}
+		}
+
+		// Optional seller concessions and gift funds lower the buyer's cash at purchase (default 0)
+		inputs.SellerConcessions, err = getFloatValue(values, "seller_concessions")
+		if err != nil || inputs.SellerConcessions < 0 {
+			return inputs, fmt.Errorf("invalid seller concessions - must be an amount of zero or more")
+		}
+		inputs.GiftFunds, err = getFloatValue(values, "gift_funds")
+		if err != nil || inputs.GiftFunds < 0 || inputs.GiftFunds > inputs.Downpayment {
+			return inputs, fmt.Errorf("invalid gift funds - must be an amount from zero up to the downpayment")
+		}
+		if closing := inputs.Downpayment + inputs.PurchasePrice*inputs.TransferTaxPct/100 + inputs.StampDuty; inputs.SellerConcessions+inputs.GiftFunds > closing {
+			return inputs, fmt.Errorf("invalid seller concessions - with gift funds, can't exceed the cash due at purchase (%s)", formatCurrency(closing))
+		}
+
+		// Optional gross income for the affordability (debt-to-income) check
+		if strings.TrimSpace(values["gross_monthly_income"]) != "" {
+			inputs.GrossMonthlyIncome, err = getFloatValue(values, "gross_monthly_income")
+			if err != nil || inputs.GrossMonthlyIncome < 0 {
+				return inputs, fmt.Errorf("invalid gross monthly income - must be a monthly amount of zero or more")
+			}
+		}
 
-		if config.loanAmount > 0 {
-			config.annualRate, err = getFloatValue("loan_rate")
+		investmentProperty, _ := getFloatValue(values, "investment_property")
+		if investmentProperty > 0 {
+			inputs.InvestmentProperty = true
+			inputs.IncomeTaxRate, err = getFloatValue(values, "income_tax_rate")
 			if err != nil {
-				return fmt.Errorf("invalid loan rate: %v", err)
+				return inputs, fmt.Errorf("invalid income tax rate: %v", err)
+			}
+			if strings.TrimSpace(values["rental_income"]) != "" {
+				inputs.RentalIncome, err = getFloatValue(values, "rental_income")
+				if err != nil || inputs.RentalIncome < 0 {
+					return inputs, fmt.Errorf("invalid rental income - must be a monthly amount of zero or more")
+				}
 			}
+		}
 
-			config.totalMonths, err = getIntValue("loan_term", parseDuration)
+		if inputs.LoanAmount > 0 {
+			inputs.AnnualRate, err = getFloatValue(values, "loan_rate")
 			if err != nil {
-				return fmt.Errorf("invalid loan term: %v", err)
+				return inputs, fmt.Errorf("invalid loan rate: %v", err)
 			}
 
-			config.monthlyRate = config.annualRate / 100 / 12
-			config.monthlyLoanPayment = calculateMonthlyPayment(config.loanAmount, config.monthlyRate, config.totalMonths)
-		} else {
-			config.annualRate = 0
-			config.totalMonths = 0
-			config.monthlyRate = 0
-			config.monthlyLoanPayment = 0
+			inputs.LoanMonths, err = getIntValue(values, "loan_term", parseDuration)
+			if err != nil {
+				return inputs, fmt.Errorf("invalid loan term: %v", err)
+			}
+
+			// Optional calendar month of the first payment, for interest totals by tax year
+			if loanStart := strings.TrimSpace(values["loan_start"]); loanStart != "" {
+				inputs.LoanStart, err = time.Parse("2006-01", loanStart)
+				if err != nil {
+					return inputs, fmt.Errorf("invalid loan start %q - expected YYYY-MM", loanStart)
+				}
+			}
+
+			// Interest day-count convention: 30/360 (default) or actual/365
+			switch dayCount := strings.ToLower(strings.TrimSpace(values["day_count"])); dayCount {
+			case "", "30/360":
+			case "actual/365":
+				if inputs.LoanStart.IsZero() {
+					return inputs, fmt.Errorf("actual/365 day count needs a loan start date")
+				}
+				if inputs.SemiAnnualCompounding {
+					return inputs, fmt.Errorf("actual/365 day count needs monthly compounding")
+				}
+				inputs.ActualDayCount = true
+			default:
+				return inputs, fmt.Errorf("invalid day count %q - expected 30/360 or actual/365", dayCount)
+			}
+
+			// Loan payment frequency: monthly (default), semimonthly, biweekly or weekly
+			if frequency := strings.ToLower(strings.TrimSpace(values["payment_frequency"])); frequency != "" {
+				perYear, ok := calc.PaymentFrequencies[frequency]
+				if !ok {
+					return inputs, fmt.Errorf("invalid payment frequency %q - expected monthly, semimonthly, biweekly or weekly", frequency)
+				}
+				if perYear != 12 && inputs.ActualDayCount {
+					return inputs, fmt.Errorf("actual/365 day count needs monthly payments")
+				}
+				inputs.PaymentsPerYear = perYear
+			}
 		}
 	}
 
-	// Calculate derived monthly costs
-	totalAnnualExpenses := config.annualInsurance + config.annualTaxes
-	monthlyRecurringExpenses := (totalAnnualExpenses / 12) + config.monthlyExpenses
-	config.totalMonthlyBuyingCost = config.monthlyLoanPayment + monthlyRecurringExpenses
+	// Optional recast: a lump-sum prepayment that lowers the payment for the rest of the term
+	inputs.RecastAmount, err = getFloatValue(values, "recast_amount")
+	if err != nil {
+		inputs.RecastAmount = 0
+	}
+	if inputs.RecastAmount > 0 && inputs.LoanAmount > 0 {
+		recastMonth, err := getFloatValue(values, "recast_month")
+		if err != nil || recastMonth <= 0 || int(recastMonth) >= inputs.LoanMonths {
+			return inputs, fmt.Errorf("invalid recast month - must be within the loan term (1-%d)", inputs.LoanMonths-1)
+		}
+		inputs.RecastMonth = int(recastMonth)
 
-	monthlyRentingExpenses := (config.annualRentCosts / 12) + (config.otherAnnualCosts / 12)
-	config.totalMonthlyRentingCost = config.monthlyRent + monthlyRentingExpenses
+		// Optional yearly overpayment allowance, with an early repayment charge above it
+		if strings.TrimSpace(values["overpayment_cap_pct"]) != "" {
+			inputs.OverpaymentCapPct, err = getFloatValue(values, "overpayment_cap_pct")
+			if err != nil || inputs.OverpaymentCapPct <= 0 || inputs.OverpaymentCapPct > 100 {
+				return inputs, fmt.Errorf("invalid overpayment cap - must be a %% of the balance from 0 to 100")
+			}
+			inputs.OverpaymentPenaltyPct, err = getFloatValue(values, "overpayment_penalty_pct")
+			if err != nil || inputs.OverpaymentPenaltyPct < 0 {
+				inputs.OverpaymentPenaltyPct = 0
+			}
+		}
+	}
 
-	return nil
-}
+	// Optional fixed-rate period, after which the loan reverts to a standard variable rate (UK)
+	if svrRate := strings.TrimSpace(values["svr_rate"]); svrRate != "" && inputs.LoanAmount > 0 {
+		inputs.SVRRate, err = getFloatValue(values, "svr_rate")
+		if err != nil {
+			return inputs, fmt.Errorf("invalid SVR rate: %v", err)
+		}
+		inputs.FixedMonths, err = parseDuration(values["fixed_period"])
+		if err != nil || inputs.FixedMonths >= inputs.LoanMonths {
+			return inputs, fmt.Errorf("invalid fixed period - must be shorter than the loan term when an SVR rate is given")
+		}
+		if inputs.ActualDayCount {
+			return inputs, fmt.Errorf("actual/365 day count can't be combined with an SVR rate")
+		}
+	}
 
-// runBuyVsRentScenario handles the BUY vs RENT scenario calculations and display
-func runBuyVsRentScenario(marketData *MarketData) {
-	// All configuration is already parsed in config global variable
+	// Optional offset savings (UK offset mortgage): interest accrues on the balance less the savings
+	if strings.TrimSpace(values["offset_savings"]) != "" {
+		inputs.OffsetSavings, err = getFloatValue(values, "offset_savings")
+		if err != nil || inputs.OffsetSavings < 0 {
+			return inputs, fmt.Errorf("invalid offset savings - must be an amount of zero or more")
+		}
+	}
+
+	// Optional living area for cost-per-square-foot metrics
+	inputs.SquareFeet, err = getFloatValue(values, "square_feet")
+	if err != nil || inputs.SquareFeet < 0 {
+		inputs.SquareFeet = 0
+	}
 
-	// Populate global cost arrays for projections
-	populateMonthlyCosts()
+	// Project far enough to cover any custom periods
+	if len(customPeriods) > 0 {
+		inputs.ProjectionMonths = customPeriods[len(customPeriods)-1]
+	}
 
+	return inputs, nil
+}
+
+// runBuyVsRentScenario handles the BUY vs RENT scenario calculations and display
+func runBuyVsRentScenario(scenario *calc.Scenario, marketData *MarketData) {
 	// Display input parameters
-	displayInputParameters(marketData)
+	displayInputParameters(scenario, marketData)
 
 	// Display market data after input parameters
 	displayMarketData(marketData)
 
+	// Display where the money goes over the loan term
+	displayTCOSummary(scenario)
+
 	// Display projections
-	displayExpenditureTable()
+	displayExpenditureTable(scenario)
+
+	if scenario.LoanAmount > 0 {
+		displayAmortizationTable(scenario)
+	}
+
+	if scenario.IncludeSelling {
+		displaySaleProceeds(scenario)
+	}
+
+	if scenario.InvestmentProperty {
+		displayInvestmentMetrics(scenario)
+	}
 
-	if config.loanAmount > 0 {
-		displayAmortizationTable()
+	if scenario.HasRentalIncome() {
+		displayCashOnCash(scenario)
 	}
 
-	if config.includeSelling > 0 {
-		displaySaleProceeds()
+	displayComparisonTable(scenario)
+	logRun(scenario, false)
+
+	displayNetWorthBars(scenario)
+
+	if discountRate != 0 {
+		displayNPVComparison(scenario, discountRate)
 	}
 
-	displayComparisonTable()
+	if monteCarloTrials > 0 {
+		displayMonteCarlo(scenario, monteCarloTrials, marketData)
+	}
+
+	displayVerdict(scenario)
 }
 
 // runSellVsKeepScenario handles the SELL vs KEEP scenario calculations and display
-func runSellVsKeepScenario(marketData *MarketData) {
-	// All configuration is already parsed in config global variable
-	// config.purchasePrice = original purchase price (for capital gains)
-	// config.currentMarketValue = current market value
-	// config.loanAmount = remaining loan balance (calculated in parseConfig)
-	// config.downpayment = current equity (currentMarketValue - loanAmount)
-
-	// Populate global cost arrays for KEEP scenario (continuing to own)
-	populateMonthlyCosts()
+func runSellVsKeepScenario(scenario *calc.Scenario, marketData *MarketData) {
+	// All configuration is already parsed into the scenario
+	// scenario.PurchasePrice = original purchase price (for capital gains)
+	// scenario.CurrentMarketValue = current market value
+	// scenario.LoanAmount = remaining loan balance (calculated in parseConfig)
+	// scenario.Downpayment = current equity (CurrentMarketValue - LoanAmount)
 
 	// Display input parameters
-	displayInputParametersSellVsKeep(marketData)
+	displayInputParametersSellVsKeep(scenario, marketData)
 
 	// Display market data
 	displayMarketData(marketData)
 
 	// Display loan amortization if there's a remaining loan
-	if config.loanAmount > 0 {
-		displayAmortizationTable()
+	if scenario.LoanAmount > 0 {
+		displayAmortizationTable(scenario)
 	}
 
 	// Display expense breakdowns
-	includeRenting, _ := getFloatValue("include_renting_sell")
-	if includeRenting > 0 {
-		displaySellExpensesBreakdown()
+	if scenario.IncludeRenting {
+		displaySellExpensesBreakdown(scenario)
 	}
-	displayKeepExpensesBreakdown()
+	displayKeepExpensesBreakdown(scenario)
 
 	// Display sale proceeds analysis at various future periods
-	displaySaleProceeds()
+	displaySaleProceeds(scenario)
 
 	// Display SELL vs KEEP comparison
-	displaySellVsKeepComparison()
+	displaySellVsKeepComparison(scenario)
+	logRun(scenario, true)
 }
 
-// getFloatValue gets a float value from currentInputs
-func getFloatValue(key string) (float64, error) {
-	input := currentInputs[key]
+// getFloatValue gets a float value from the inputs
+func getFloatValue(values map[string]string, key string) (float64, error) {
+	input := values[key]
 	value, err := parseAmount(input)
 	return value, err
 }
 
-// getIntValue gets an int value from currentInputs with a parser
-func getIntValue(key string, parser func(string) (int, error)) (int, error) {
-	input := currentInputs[key]
+// getIntValue gets an int value from the inputs with a parser
+func getIntValue(values map[string]string, key string, parser func(string) (int, error)) (int, error) {
+	input := values[key]
 	value, err := parser(input)
 	return value, err
 }
@@ -429,6 +913,27 @@ func loadInputs() map[string]string {
 	return inputs
 }
 
+// readInputs reads inputs in the saved inputs JSON format from r
+func readInputs(r io.Reader) (map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %v", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, fmt.Errorf("no input received")
+	}
+
+	var inputs map[string]string
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of string values: %v", err)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no input fields found")
+	}
+
+	return inputs, nil
+}
+
 // saveInputs saves current inputs to file for next run
 func saveInputs(inputs map[string]string) {
 	data, err := json.Marshal(inputs)
@@ -478,8 +983,90 @@ func parseAmount(input string) (float64, error) {
 	return value * multiplier, nil
 }
 
+// formatMoveSchedule describes the renter's moving costs, e.g. "8.0K every 3y (inflated)"
+func formatMoveSchedule(scenario *calc.Scenario) string {
+	return fmt.Sprintf("%s every %s (inflated)", formatCurrency(scenario.MoveCost), strings.TrimSpace(formatPeriodLabel(scenario.MoveEveryMonths)))
+}
+
+// priceToRentGuidance interprets a price-to-rent ratio by the usual rule of thumb
+func priceToRentGuidance(ratio float64) string {
+	switch {
+	case ratio < 15:
+		return "below 15 favors buying"
+	case ratio > 21:
+		return "above 21 favors renting"
+	default:
+		return "15-21 is a toss-up"
+	}
+}
+
+// taxCapTarget names what the reassessment cap limits
+func taxCapTarget(scenario *calc.Scenario) string {
+	if scenario.PropertyTaxRate > 0 {
+		return "Property Tax"
+	}
+	return "Tax & Insurance"
+}
+
+// formatImprovements lists the non-zero capital improvements by year, or "" if there are none
+func formatImprovements(improvements []float64) string {
+	var parts []string
+	for i, amount := range improvements {
+		if amount != 0 {
+			parts = append(parts, fmt.Sprintf("%s (year %d)", formatCurrency(amount), i+1))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatAppreciation formats one year's appreciation as a percentage, or as dollars per year in dollar mode
+func formatAppreciation(scenario *calc.Scenario, rate float64) string {
+	if scenario.AppreciationInDollars {
+		return formatCurrency(rate) + "/yr"
+	}
+	return fmt.Sprintf("%.2f%%", rate)
+}
+
+// formatRateSchedule formats per-year rates with the given decimals, e.g. "8.0% (year 1), 3.0% (year 2+)"
+// A single rate is formatted on its own
+func formatRateSchedule(rates []float64, decimals int) string {
+	if len(rates) == 1 {
+		return fmt.Sprintf("%.*f%%", decimals, rates[0])
+	}
+
+	return formatYearSchedule(rates, func(rate float64) string {
+		return fmt.Sprintf("%.*f%%", decimals, rate)
+	})
+}
+
+// formatYearSchedule formats a yearly schedule whose last value applies to all remaining years.
+// Runs of the same value are shown once with their years (e.g., "3.00% (years 1-5)").
+func formatYearSchedule(values []float64, format func(float64) string) string {
+	var strs []string
+	for start := 0; start < len(values); {
+		end := start
+		for end+1 < len(values) && values[end+1] == values[start] {
+			end++
+		}
+		if end == len(values)-1 {
+			strs = append(strs, fmt.Sprintf("%s (year %d+)", format(values[start]), start+1))
+		} else if end > start {
+			strs = append(strs, fmt.Sprintf("%s (years %d-%d)", format(values[start]), start+1, end+1))
+		} else {
+			strs = append(strs, fmt.Sprintf("%s (year %d)", format(values[start]), start+1))
+		}
+		start = end + 1
+	}
+	return strings.Join(strs, ", ")
+}
+
+// maxScheduleRepeat caps the year count of a repeated rate (e.g., '3x5') in a schedule
+const maxScheduleRepeat = 100
+
 // parseAppreciationRates parses comma-separated appreciation rates
-// Returns array where each entry corresponds to a year, with the last entry applying to all future years
+// Returns array where each entry corresponds to a year, with the last entry applying to all future years.
+// A rate can be repeated for several years as NxM (e.g., '3x5,1' = 3% for five years, then 1%),
+// or ramped linearly as start..end:years (e.g., '5..2:4' = 5%, 4%, 3%, 2%).
 func parseAppreciationRates(input string) ([]float64, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
@@ -491,11 +1078,30 @@ func parseAppreciationRates(input string) ([]float64, error) {
 	rates := make([]float64, 0, len(parts))
 
 	for _, part := range parts {
-		rate, err := parseAmount(part)
+		if strings.Contains(part, "..") {
+			ramp, err := parseRamp(part)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, ramp...)
+			continue
+		}
+
+		value, repeat, hasRepeat := strings.Cut(strings.ToLower(part), "x")
+		rate, err := parseAmount(value)
 		if err != nil {
 			return nil, fmt.Errorf("invalid rate '%s': %v", strings.TrimSpace(part), err)
 		}
-		rates = append(rates, rate)
+		count := 1
+		if hasRepeat {
+			count, err = strconv.Atoi(strings.TrimSpace(repeat))
+			if err != nil || count < 1 || count > maxScheduleRepeat {
+				return nil, fmt.Errorf("invalid repetition '%s': the count after 'x' must be a whole number of years from 1 to %d", strings.TrimSpace(part), maxScheduleRepeat)
+			}
+		}
+		for range count {
+			rates = append(rates, rate)
+		}
 	}
 
 	if len(rates) == 0 {
@@ -505,6 +1111,35 @@ func parseAppreciationRates(input string) ([]float64, error) {
 	return rates, nil
 }
 
+// parseRamp expands a linear ramp like '5..2:4' into one value per year, from the start value
+// in the first year to the end value in the last
+func parseRamp(part string) ([]float64, error) {
+	part = strings.TrimSpace(part)
+	bounds, yearsStr, ok := strings.Cut(part, ":")
+	startStr, endStr, _ := strings.Cut(bounds, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid ramp '%s': expected start..end:years (e.g., 5..2:4)", part)
+	}
+	start, err := parseAmount(startStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ramp start in '%s': %v", part, err)
+	}
+	end, err := parseAmount(endStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ramp end in '%s': %v", part, err)
+	}
+	years, err := strconv.Atoi(strings.TrimSpace(yearsStr))
+	if err != nil || years < 2 || years > maxScheduleRepeat {
+		return nil, fmt.Errorf("invalid ramp '%s': the years after ':' must be a whole number from 2 to %d", part, maxScheduleRepeat)
+	}
+
+	values := make([]float64, years)
+	for i := range values {
+		values[i] = start + (end-start)*float64(i)/float64(years-1)
+	}
+	return values, nil
+}
+
 // getStringInputAndParse prompts the user and applies a parser function
 func getStringInputAndParse(prompt string, parser func(string) (int, error)) (int, error) {
 	fmt.Print(prompt)
@@ -512,16 +1147,20 @@ func getStringInputAndParse(prompt string, parser func(string) (int, error)) (in
 	return parser(strings.TrimSpace(input))
 }
 
+// cellStyleFunc optionally overrides the style of an individual table cell
+type cellStyleFunc func(row, col int, style lipgloss.Style) lipgloss.Style
+
 // displayTable displays a formatted table with title and optional notes
-func displayTable(title string, rows [][]string, notes string, highlightLastRow bool) {
-	re := lipgloss.NewRenderer(os.Stdout)
+// cellStyle may be nil; when set, it can adjust the style of any cell after the default styling
+func displayTable(title string, rows [][]string, notes string, highlightLastRow bool, cellStyle cellStyleFunc) {
+	re := newRenderer()
 
 	// Title style
-	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
 
 	// Table styles
-	headerStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiCyan).Bold(true)
-	rowStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiAdaptiveText)
+	headerStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Accent).Bold(true)
+	rowStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Text)
 
 	// Print title
 	fmt.Println()
@@ -530,7 +1169,7 @@ func displayTable(title string, rows [][]string, notes string, highlightLastRow
 	// Create table
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
-		BorderStyle(re.NewStyle().Foreground(MonokaiBorder)).
+		BorderStyle(re.NewStyle().Foreground(activeTheme.Border)).
 		Rows(rows...).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			var style lipgloss.Style
@@ -546,22 +1185,88 @@ func displayTable(title string, rows [][]string, notes string, highlightLastRow
 				style = style.Align(lipgloss.Right)
 			}
 
+			// Apply per-cell overrides
+			if cellStyle != nil {
+				style = cellStyle(row, col, style)
+			}
+
 			return style
 		})
 
+	// Constrain the table to the terminal width (columns wrap instead of overflowing)
+	width := terminalWidth()
+	if width > 0 && tableWidth(rows) > width {
+		t = t.Width(width)
+	}
+
 	fmt.Println(t)
 
 	// Print notes if provided
 	if notes != "" {
-		noteStyle := re.NewStyle().Width(100).Italic(true).Foreground(MonokaiGrey).PaddingLeft(2)
+		noteWidth := 100
+		if width > 0 && width < noteWidth {
+			noteWidth = width
+		}
+		noteStyle := re.NewStyle().Width(noteWidth).Italic(true).Foreground(activeTheme.Muted).PaddingLeft(2)
 		fmt.Println(noteStyle.Render(notes))
 	}
 }
 
+// terminalWidth returns the width of the terminal attached to stdout, or 0 if stdout isn't a terminal
+func terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// tableWidth returns the rendered width of a bordered table with one space of padding per side
+func tableWidth(rows [][]string) int {
+	if len(rows) == 0 {
+		return 0
+	}
+
+	width := 1 // Left border
+	for col := range rows[0] {
+		colWidth := 0
+		for _, row := range rows {
+			if col < len(row) {
+				colWidth = max(colWidth, lipgloss.Width(row[col]))
+			}
+		}
+		width += colWidth + 2 + 1 // Padding and right border
+	}
+	return width
+}
+
+// dropColumn removes the column with the given header from all rows
+func dropColumn(rows [][]string, header string) [][]string {
+	col := -1
+	for i, name := range rows[0] {
+		if name == header {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return rows
+	}
+
+	result := make([][]string, len(rows))
+	for i, row := range rows {
+		result[i] = append(append([]string{}, row[:col]...), row[col+1:]...)
+	}
+	return result
+}
+
 // formatCurrency formats a number as currency with K/M suffixes (compact) or full format
 func formatCurrency(amount float64) string {
-	// Handle negative numbers
+	// Handle negative numbers; anything that rounds to zero drops its sign instead of showing -0.0
 	sign := ""
+	if math.Abs(amount) < 0.05 {
+		amount = 0
+	}
 	if amount < 0 {
 		sign = "-"
 		amount = -amount
@@ -586,12 +1291,15 @@ func formatCurrency(amount float64) string {
 		return fmt.Sprintf("%s$%s.%s", sign, result.String(), parts[1])
 	}
 
-	// Default: compact format with K/M suffixes, no dollar sign (automatically rounds)
+	// Default: compact format with K/M suffixes, no dollar sign (automatically rounds).
+	// The suffix is picked from the rounded value, so 999.95 shows as 1.0K rather than 1000.0.
+	units, _ := strconv.ParseFloat(fmt.Sprintf("%.1f", amount), 64)
+	thousands, _ := strconv.ParseFloat(fmt.Sprintf("%.1f", amount/1000), 64)
 	var formatted string
-	if amount >= 1000000 {
+	if thousands >= 1000 {
 		// Millions
 		formatted = fmt.Sprintf("%.1fM", amount/1000000)
-	} else if amount >= 1000 {
+	} else if units >= 1000 {
 		// Thousands
 		formatted = fmt.Sprintf("%.1fK", amount/1000)
 	} else {
@@ -617,6 +1325,37 @@ func formatNumber(num int) string {
 	return result.String()
 }
 
+// parseStampDutyBands parses stamp duty bands like "125K:0,250K:2,5": each limit:rate applies
+// the rate (%) up to the limit, and a final bare rate applies to the rest of the price
+func parseStampDutyBands(input string) ([]calc.StampDutyBand, error) {
+	var bands []calc.StampDutyBand
+	parts := strings.Split(input, ",")
+	for i, part := range parts {
+		limitStr, rateStr, hasLimit := strings.Cut(strings.TrimSpace(part), ":")
+		if !hasLimit {
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("invalid stamp duty band %q - only the last band can omit its limit", part)
+			}
+			rateStr, limitStr = limitStr, ""
+		}
+
+		var band calc.StampDutyBand
+		var err error
+		band.Rate, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(rateStr), "%"), 64)
+		if err != nil || band.Rate < 0 {
+			return nil, fmt.Errorf("invalid stamp duty rate in band %q", part)
+		}
+		if hasLimit {
+			band.UpTo, err = parseAmount(limitStr)
+			if err != nil || band.UpTo <= 0 || (len(bands) > 0 && band.UpTo <= bands[len(bands)-1].UpTo) {
+				return nil, fmt.Errorf("invalid stamp duty band limit in %q - limits must be positive and increasing", part)
+			}
+		}
+		bands = append(bands, band)
+	}
+	return bands, nil
+}
+
 // parseDuration parses duration strings like "5y6m", "30y", "6m"
 func parseDuration(duration string) (int, error) {
 	duration = strings.ToLower(duration)
@@ -654,16 +1393,44 @@ func parseDuration(duration string) (int, error) {
 	return totalMonths, nil
 }
 
-// calculateMonthlyPayment calculates the monthly payment using the amortization formula
-// M = P * [r(1+r)^n] / [(1+r)^n - 1]
-func calculateMonthlyPayment(principal, monthlyRate float64, months int) float64 {
-	if monthlyRate == 0 {
-		return principal / float64(months)
+// parsePeriods parses comma-separated durations like "2y,7y,12y" into sorted, unique month counts
+func parsePeriods(input string) ([]int, error) {
+	seen := make(map[int]bool)
+	var periods []int
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		months, err := parseDuration(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period '%s': %v", part, err)
+		}
+		if !seen[months] {
+			seen[months] = true
+			periods = append(periods, months)
+		}
+	}
+
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("no periods given")
 	}
 
-	factor := math.Pow(1+monthlyRate, float64(months))
-	monthlyPayment := principal * (monthlyRate * factor) / (factor - 1)
-	return monthlyPayment
+	sort.Ints(periods)
+	return periods, nil
+}
+
+// formatPeriodLabel formats a month count as a right-aligned period label like " 10y" or "1y6m"
+func formatPeriodLabel(months int) string {
+	label := fmt.Sprintf("%dy", months/12)
+	if months%12 != 0 {
+		if months < 12 {
+			label = fmt.Sprintf("%dm", months)
+		} else {
+			label = fmt.Sprintf("%dy%dm", months/12, months%12)
+		}
+	}
+	return fmt.Sprintf("%4s", label)
 }
 
 // getPeriods returns the list of time periods to display in tables
@@ -704,6 +1471,28 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 		standardPeriods = append(standardPeriods, extendedPeriods...)
 	}
 
+	// Custom periods from --periods replace the standard set
+	if len(customPeriods) > 0 {
+		standardPeriods = nil
+		for _, months := range customPeriods {
+			standardPeriods = append(standardPeriods, struct {
+				label  string
+				months int
+			}{formatPeriodLabel(months), months})
+		}
+	}
+
+	// Annual mode expands the periods into one row per year up to the horizon
+	if horizon := standardPeriods[len(standardPeriods)-1].months; annualPeriods && horizon >= 12 {
+		standardPeriods = nil
+		for months := 12; months <= horizon; months += 12 {
+			standardPeriods = append(standardPeriods, struct {
+				label  string
+				months int
+			}{formatPeriodLabel(months), months})
+		}
+	}
+
 	// Build the final list of periods, inserting loan term if needed (only if it's a full year)
 	periods := []struct {
 		label  string
@@ -754,94 +1543,205 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 }
 
 // displayInputParameters displays all input parameters in grouped format
-func displayInputParameters(md *MarketData) {
-	re := lipgloss.NewRenderer(os.Stdout)
-	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
-	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
-	groupStyle := re.NewStyle().Foreground(MonokaiOrange).Bold(true)
+func displayInputParameters(scenario *calc.Scenario, md *MarketData) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	groupStyle := re.NewStyle().Foreground(activeTheme.Secondary).Bold(true)
 
 	fmt.Println()
 	fmt.Println(titleStyle.Render("INPUT PARAMETERS"))
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ECONOMIC ASSUMPTIONS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Inflation Rate"), config.inflationRate)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Investment Return Rate"), config.investmentReturnRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Inflation Rate"), formatRateSchedule(scenario.InflationRates, 2))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Investment Return Rate"), formatRateSchedule(scenario.InvestmentReturnRates, 2))
+	if scenario.InvestmentFee != 0 {
+		fmt.Printf("  %s: %.2f%% per year (net return %s)\n", labelStyle.Render("Investment Fees"), scenario.InvestmentFee, formatRateSchedule(scenario.NetInvestmentReturnRates(), 2))
+	}
 
 	// Display market averages with ticker symbols in cyan
 	if md != nil && len(md.VOO) > 0 {
-		vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(md)
-		if vooAvg > 0 {
-			tickerStyle := re.NewStyle().Foreground(MonokaiCyan)
+		avg := calculateMarketAverages(md)
+		if avg.VOO > 0 {
+			tickerStyle := re.NewStyle().Foreground(activeTheme.Accent)
 			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%\n",
-				tickerStyle.Render("VOO"), vooAvg,
-				tickerStyle.Render("QQQ"), qqqAvg,
-				tickerStyle.Render("VTI"), vtiAvg,
-				tickerStyle.Render("BND"), bndAvg,
-				tickerStyle.Render("60/40"), mix6040Avg)
+				tickerStyle.Render("VOO"), avg.VOO,
+				tickerStyle.Render("QQQ"), avg.QQQ,
+				tickerStyle.Render("VTI"), avg.VTI,
+				tickerStyle.Render("BND"), avg.BND,
+				tickerStyle.Render("60/40"), avg.Mix6040)
 		}
 	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("BUYING"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Asset Purchase Price"), formatCurrency(config.purchasePrice))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Amount"), formatCurrency(config.loanAmount))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Downpayment"), formatCurrency(config.downpayment))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.annualRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Asset Purchase Price"), formatCurrency(scenario.PurchasePrice))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Amount"), formatCurrency(scenario.LoanAmount))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Downpayment"), formatCurrency(scenario.Downpayment))
+	if scenario.TransferTaxPct > 0 {
+		atSale := ""
+		if scenario.TransferTaxOnSale {
+			atSale = ", and again at sale"
+		}
+		fmt.Printf("  %s: %.2f%% (%s at purchase%s)\n", labelStyle.Render("Transfer Tax"), scenario.TransferTaxPct, formatCurrency(scenario.PurchasePrice*scenario.TransferTaxPct/100), atSale)
+	}
+	if scenario.StampDuty > 0 {
+		fmt.Printf("  %s: %s at purchase (%.2f%% of price)\n", labelStyle.Render("Stamp Duty"), formatCurrency(scenario.StampDuty), scenario.StampDuty/scenario.PurchasePrice*100)
+	}
+	if scenario.SellerConcessions > 0 {
+		fmt.Printf("  %s: %s credited at purchase (also lowers the cost basis)\n", labelStyle.Render("Seller Concessions"), formatCurrency(scenario.SellerConcessions))
+	}
+	if scenario.GiftFunds > 0 {
+		fmt.Printf("  %s: %s toward the downpayment\n", labelStyle.Render("Gift Funds"), formatCurrency(scenario.GiftFunds))
+	}
+	if scenario.SellerConcessions > 0 || scenario.GiftFunds > 0 {
+		fmt.Printf("  %s: %s (what the renter invests instead)\n", labelStyle.Render("Buyer's Cash at Purchase"), formatCurrency(scenario.UpfrontCash()))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Rate"), formatLoanRate(scenario))
 
 	// Format loan duration
 	loanDurationStr := ""
-	if config.totalMonths%12 == 0 {
-		loanDurationStr = fmt.Sprintf("%dy", config.totalMonths/12)
+	if scenario.LoanMonths%12 == 0 {
+		loanDurationStr = fmt.Sprintf("%dy", scenario.LoanMonths/12)
 	} else {
-		loanDurationStr = fmt.Sprintf("%d months", config.totalMonths)
+		loanDurationStr = fmt.Sprintf("%d months", scenario.LoanMonths)
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Duration"), loanDurationStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.annualInsurance))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.annualTaxes))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.monthlyExpenses))
+	if scenario.HasLoanStart() {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("First Payment"), scenario.LoanStart.Format("Jan 2006"))
+	}
+	if scenario.ActualDayCount {
+		fmt.Printf("  %s: actual/365\n", labelStyle.Render("Interest Day Count"))
+	}
+	if scenario.PaymentsPerYear != 12 {
+		fmt.Printf("  %s: %s, %d payments/yr of %s (%s/mo on average)\n", labelStyle.Render("Payment Frequency"), paymentFrequencyName(scenario.PaymentsPerYear),
+			scenario.PaymentsPerYear, formatCurrency(scenario.MonthlyLoanPayment*12/float64(scenario.PaymentsPerYear)), formatCurrency(scenario.MonthlyLoanPayment))
+	}
+	if scenario.SVRPayment > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Rate After Fixed Period"), formatSVR(scenario))
+	}
+	if scenario.OffsetSavings > 0 {
+		fmt.Printf("  %s: %s (no interest on that much of the balance)\n", labelStyle.Render("Offset Savings"), formatCurrency(scenario.OffsetSavings))
+	}
+	if scenario.PropertyTaxRate > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Insurance"), formatCurrency(scenario.AnnualInsurance))
+		fmt.Printf("  %s: %.2f%% of value (%s in year 1)\n", labelStyle.Render("Property Tax"), scenario.PropertyTaxRate, formatCurrency(scenario.PropertyTaxAt(0)))
+	} else {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(scenario.AnnualInsurance))
+	}
+	if scenario.CapPropertyTax {
+		fmt.Printf("  %s: %.2f%%/yr (%s growth)\n", labelStyle.Render("Tax Reassessment Cap"), scenario.TaxReassessmentCap, taxCapTarget(scenario))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(scenario.AnnualTaxes))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(scenario.MonthlyExpenses))
+	if scenario.ReplacementReserve != 0 {
+		fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Replacement Reserve"), formatCurrency(scenario.ReplacementReserve))
+	}
+	if scenario.BuyUtilities != 0 {
+		fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Utilities"), formatCurrency(scenario.BuyUtilities))
+	}
+	displayLeaseholdCharges(scenario, labelStyle)
+	displayCouncilTax(scenario, labelStyle)
 
 	// Format appreciation rates
 	appreciationRateStr := ""
-	if len(appreciationRates) == 1 {
-		appreciationRateStr = fmt.Sprintf("%.2f%% (all years)", appreciationRates[0])
+	if len(scenario.AppreciationRates) == 1 {
+		appreciationRateStr = fmt.Sprintf("%s (all years)", formatAppreciation(scenario, scenario.AppreciationRates[0]))
 	} else {
-		rateStrs := make([]string, len(appreciationRates))
-		for i, rate := range appreciationRates {
-			if i == len(appreciationRates)-1 {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d+)", rate, i+1)
-			} else {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d)", rate, i+1)
-			}
-		}
-		appreciationRateStr = strings.Join(rateStrs, ", ")
+		appreciationRateStr = formatYearSchedule(scenario.AppreciationRates, func(rate float64) string {
+			return formatAppreciation(scenario, rate)
+		})
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Appreciation Rate"), appreciationRateStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.totalMonthlyBuyingCost))
+	if improvements := formatImprovements(scenario.CapitalImprovements); improvements != "" {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Capital Improvements"), improvements)
+	}
+	if scenario.InvestmentProperty {
+		fmt.Printf("  %s: %s/yr over 27.5y, tax shield at %.2f%%\n", labelStyle.Render("Depreciation"), formatCurrency(scenario.MonthlyDepreciation()*12), scenario.IncomeTaxRate)
+	}
+	if scenario.HasRentalIncome() {
+		fmt.Printf("  %s: %s/mo (inflated yearly)\n", labelStyle.Render("Rental Income"), formatCurrency(scenario.RentalIncome))
+	}
+	if scenario.RebuyYear > 0 {
+		newLoan := "no new loan"
+		if scenario.RebuyLoanAmount > 0 {
+			newLoan = fmt.Sprintf("new loan %s at %s/mo", formatCurrency(scenario.RebuyLoanAmount), formatCurrency(scenario.RebuyPayment))
+		}
+		fmt.Printf("  %s: after %s into a %s home (%s proceeds, %s)\n", labelStyle.Render("Sell & Re-buy"),
+			formatHorizon(scenario.RebuyYear*12), formatCurrency(scenario.RebuyPrice), formatCurrency(scenario.RebuyProceeds), newLoan)
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(scenario.TotalMonthlyBuyingCost))
+	if scenario.SquareFeet > 0 {
+		fmt.Printf("  %s: %.2f/sq ft per month\n", labelStyle.Render("Cost per Sq Ft"), scenario.TotalMonthlyBuyingCost/scenario.SquareFeet)
+	}
+	effectiveNote := "year 1 cost less principal paydown and appreciation"
+	if scenario.ImprovementsBefore(12) > 0 {
+		effectiveNote += ", excluding improvements"
+	}
+	fmt.Printf("  %s: %s/mo vs %s rent (%s)\n", labelStyle.Render("Effective Cost of Owning"),
+		formatCurrency(scenario.EffectiveMonthlyOwnershipCost()), formatCurrency(scenario.MonthlyRent), effectiveNote)
+	if scenario.GrossMonthlyIncome > 0 {
+		displayAffordability(scenario, labelStyle)
+	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("RENTING"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.rentDeposit))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.monthlyRent))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.annualRentCosts))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.otherAnnualCosts))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.totalMonthlyRentingCost))
+	if scenario.DepositEarnsReturns {
+		fmt.Printf("  %s: %s (recoverable %.0f%% grows at the investment return)\n", labelStyle.Render("Rental Deposit"), formatCurrency(scenario.RentDeposit), calc.RecoverableDepositFraction*100)
+	} else {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(scenario.RentDeposit))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(scenario.MonthlyRent))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(scenario.AnnualRentCosts))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(scenario.OtherAnnualCosts))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Renters Insurance"), formatCurrency(scenario.RentersInsurance))
+	if scenario.RentUtilities != 0 {
+		fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Utilities"), formatCurrency(scenario.RentUtilities))
+	}
+	if scenario.MoveCost > 0 && scenario.MoveEveryMonths > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Moving Costs"), formatMoveSchedule(scenario))
+	}
+	if scenario.LeaseBreakPenalty > 0 {
+		fmt.Printf("  %s: %s (if leaving mid-lease)\n", labelStyle.Render("Lease Break Penalty"), formatCurrency(scenario.LeaseBreakPenalty))
+	}
+	if scenario.CapRentIncrease {
+		fmt.Printf("  %s: %.2f%%/yr\n", labelStyle.Render("Rent Increase Cap"), scenario.RentIncreaseCap)
+	}
+	if scenario.RentFreeMonths > 0 {
+		fmt.Printf("  %s: %d\n", labelStyle.Render("Rent-Free Months"), scenario.RentFreeMonths)
+	}
+	if scenario.RentUpfrontMonths > 0 {
+		fmt.Printf("  %s: %d (%s at move-in)\n", labelStyle.Render("Rent Paid Upfront (months)"), scenario.RentUpfrontMonths, formatCurrency(scenario.UpfrontRent()))
+	}
+	if scenario.BrokerFee > 0 && scenario.MonthlyRent > 0 {
+		fmt.Printf("  %s: %s (%.1f%% of annual rent)\n", labelStyle.Render("Broker Fee"), formatCurrency(scenario.BrokerFee), scenario.BrokerFee/(scenario.MonthlyRent*12)*100)
+	} else if scenario.BrokerFee > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Broker Fee"), formatCurrency(scenario.BrokerFee))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(scenario.TotalMonthlyRentingCost))
+	if scenario.SquareFeet > 0 {
+		fmt.Printf("  %s: %.2f/sq ft per month\n", labelStyle.Render("Cost per Sq Ft"), scenario.TotalMonthlyRentingCost/scenario.SquareFeet)
+	}
+	if ratio, ok := scenario.PriceToRentRatio(); ok {
+		fmt.Printf("  %s: %.1f (%s)\n", labelStyle.Render("Price-to-Rent Ratio"), ratio, priceToRentGuidance(ratio))
+	}
 
-	if config.includeSelling > 0 {
+	if scenario.IncludeSelling {
 		fmt.Println()
 		fmt.Println(groupStyle.Render("SELLING"))
 		fmt.Printf("  %s: Yes\n", labelStyle.Render("Include Selling Analysis"))
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.agentCommission)
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.stagingCosts))
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), scenario.AgentCommission)
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(scenario.StagingCosts))
 
 		// Format tax-free limits
 		taxFreeLimitStr := ""
-		if len(taxFreeLimits) == 1 {
-			taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(taxFreeLimits[0]))
+		if len(scenario.TaxFreeLimits) == 1 {
+			taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(scenario.TaxFreeLimits[0]))
 		} else {
-			limitStrs := make([]string, len(taxFreeLimits))
-			for i, limit := range taxFreeLimits {
-				if i == len(taxFreeLimits)-1 {
+			limitStrs := make([]string, len(scenario.TaxFreeLimits))
+			for i, limit := range scenario.TaxFreeLimits {
+				if i == len(scenario.TaxFreeLimits)-1 {
 					limitStrs[i] = fmt.Sprintf("%s (year %d+)", formatCurrency(limit), i+1)
 				} else {
 					limitStrs[i] = fmt.Sprintf("%s (year %d)", formatCurrency(limit), i+1)
@@ -850,7 +1750,7 @@ func displayInputParameters(md *MarketData) {
 			taxFreeLimitStr = strings.Join(limitStrs, ", ")
 		}
 		fmt.Printf("  %s: %s\n", labelStyle.Render("Tax-Free Gains Limit"), taxFreeLimitStr)
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.capitalGainsTax)
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), scenario.CapitalGainsTax)
 	} else {
 		fmt.Println()
 		fmt.Println(groupStyle.Render("SELLING"))
@@ -858,66 +1758,168 @@ func displayInputParameters(md *MarketData) {
 	}
 }
 
+// displayTCOSummary displays the total cost of ownership over the loan term (or the projection
+// horizon without a loan) next to the rent paid over the same span
+func displayTCOSummary(scenario *calc.Scenario) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+
+	months := scenario.LoanMonths
+	span := "loan term"
+	if months == 0 {
+		months = projectionHorizon()
+		span = "projection"
+	}
+	costs := scenario.OwnershipCostsOver(months)
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render(fmt.Sprintf("TOTAL COST OF OWNERSHIP (%s %s)", strings.TrimSpace(formatPeriodLabel(months)), span)))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Interest Paid"), formatCurrency(costs.Interest))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Loan Payments"), formatCurrency(costs.LoanPayments))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Tax & Insurance"), formatCurrency(costs.TaxInsurance))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Maintenance & Expenses"), formatCurrency(costs.Maintenance))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Cost of Owning"), formatCurrency(costs.Total))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Rent Paid Instead"), formatCurrency(costs.RentPaid))
+}
+
 // displayAmortizationTable displays loan amortization details
-func displayAmortizationTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+func displayAmortizationTable(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
-		{"Period", "Principal Paid", "Interest Paid", "Loan Balance"},
+		{"Period", "Principal Paid", "Interest Paid", "Loan Balance", "LTV", "Equity"},
 	}
 
 	// Build each data row
 	for _, period := range periods {
-		monthIndex := period.months - 1
-		if monthIndex >= len(remainingLoanBalance) {
-			monthIndex = len(remainingLoanBalance) - 1
-		}
-
-		principalPaid := cumulativePrincipalPaid[monthIndex]
-		interestPaid := cumulativeInterestPaid[monthIndex]
-		loanBalance := remainingLoanBalance[monthIndex]
+		loan := scenario.AmortizationAt(period.months)
+		ltv := scenario.LoanToValueAt(period.months)
 
 		rows = append(rows, []string{
 			"LOAN " + period.label,
-			formatCurrency(principalPaid),
-			formatCurrency(interestPaid),
-			formatCurrency(loanBalance),
+			formatCurrency(loan.CumulativePrincipal),
+			formatCurrency(loan.CumulativeInterest),
+			formatCurrency(loan.Balance),
+			fmt.Sprintf("%.1f%%", ltv),
+			fmt.Sprintf("%.1f%%", 100-ltv),
 		})
 	}
 
 	notes := "Note: Monthly payment is fixed. Each payment covers interest on remaining balance, with the rest going to principal. Early payments are mostly interest."
-	displayTable("LOAN AMORTIZATION DETAILS", rows, notes, false)
-}
+	ltvNote := " LTV is the loan balance as a % of the appreciated value; PMI can usually be dropped at 80% LTV, which is also a common refinancing threshold."
+	if scenario.RecastMonth > 0 {
+		notes = fmt.Sprintf("Note: Each payment covers interest on remaining balance, with the rest going to principal. Early payments are mostly interest. After a %s lump-sum payment in month %d, the loan is recast: the monthly payment drops from %s to %s with the same rate and end date.",
+			formatCurrency(scenario.RecastAmount-scenario.OverpaymentExcess), scenario.RecastMonth, formatCurrency(scenario.MonthlyLoanPayment), formatCurrency(scenario.RecastPayment))
+	}
+	if scenario.SVRPayment > 0 {
+		notes += fmt.Sprintf(" After the %s fixed period, the rate reverts to the %.2f%% SVR and the payment is recomputed over the remaining term (%s/mo).",
+			formatHorizon(scenario.FixedMonths), scenario.SVRRate, formatCurrency(scenario.SVRPayment))
+	}
+	// Only when the schedule reached the recast (and so applied the cap)
+	if scenario.OverpaymentAllowance > 0 || scenario.OverpaymentPenalty > 0 {
+		notes += fmt.Sprintf(" Overpayments are capped at %.1f%% of the balance a year (%s allowed in month %d)", scenario.OverpaymentCapPct, formatCurrency(scenario.OverpaymentAllowance), scenario.RecastMonth)
+		if scenario.OverpaymentExcess > 0 {
+			notes += fmt.Sprintf("; the %s excess isn't applied", formatCurrency(scenario.OverpaymentExcess))
+		}
+		if scenario.OverpaymentPenalty > 0 {
+			notes += fmt.Sprintf(" and pays a %.1f%% early repayment charge of %s", scenario.OverpaymentPenaltyPct, formatCurrency(scenario.OverpaymentPenalty))
+		}
+		notes += "."
+	}
+	if scenario.PaymentsPerYear != 12 {
+		notes += fmt.Sprintf(" Payments are %s with interest accrued per payment; monthly amounts average the payments over the year.", paymentFrequencyName(scenario.PaymentsPerYear))
+	}
+	if scenario.OffsetSavings > 0 {
+		notes += fmt.Sprintf(" With %s of offset savings, interest accrues only on the balance above the savings while the payment stays the same", formatCurrency(scenario.OffsetSavings))
+		if payoff := scenario.PayoffMonth(); payoff < scenario.LoanMonths {
+			notes += fmt.Sprintf(": the loan is paid off in month %d, %s early.", payoff, formatHorizon(scenario.LoanMonths-payoff))
+		} else {
+			notes += "."
+		}
+	}
+	displayTable("LOAN AMORTIZATION DETAILS", rows, notes+ltvNote, false, nil)
 
-// displaySellExpensesBreakdown displays breakdown of rental expenses for SELL scenario
-func displaySellExpensesBreakdown() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	if scenario.HasLoanStart() {
+		displayCalendarYearInterest(scenario)
+	}
+}
 
-	// Pre-calculate annual expenses for each year (0-30 years)
-	type yearlyRentExpenses struct {
-		monthlyRent float64
-		rentCosts   float64
-		total       float64
+// displayLeaseholdCharges shows the ground rent and service charge with how each escalates
+func displayLeaseholdCharges(scenario *calc.Scenario, labelStyle lipgloss.Style) {
+	if scenario.GroundRent != 0 {
+		growth := "fixed"
+		if scenario.GroundRentIncrease != 0 {
+			growth = fmt.Sprintf("+%.2f%%/yr", scenario.GroundRentIncrease)
+		}
+		fmt.Printf("  %s: %s/yr (%s)\n", labelStyle.Render("Ground Rent"), formatCurrency(scenario.GroundRent), growth)
+	}
+	if scenario.ServiceCharge != 0 {
+		growth := "with inflation"
+		if scenario.ServiceChargeOwnRate {
+			growth = fmt.Sprintf("+%.2f%%/yr", scenario.ServiceChargeIncrease)
+		}
+		fmt.Printf("  %s: %s/yr (%s)\n", labelStyle.Render("Service Charge"), formatCurrency(scenario.ServiceCharge), growth)
 	}
+}
 
-	// Calculate expenses for each 12-month period
-	yearlyData := make([]yearlyRentExpenses, 31) // 0-30 years
+// displayCouncilTax shows the council tax with how it escalates
+func displayCouncilTax(scenario *calc.Scenario, labelStyle lipgloss.Style) {
+	if scenario.CouncilTax == 0 {
+		return
+	}
+	growth := "with inflation"
+	if scenario.CouncilTaxOwnRate {
+		growth = fmt.Sprintf("+%.2f%%/yr", scenario.CouncilTaxIncrease)
+	}
+	fmt.Printf("  %s: %s/yr (%s)\n", labelStyle.Render("Council Tax"), formatCurrency(scenario.CouncilTax), growth)
+}
 
-	for year := 0; year < 31; year++ {
-		var ye yearlyRentExpenses
+// formatLoanRate formats the nominal loan rate, with its effective monthly rate when it compounds semi-annually
+func formatLoanRate(scenario *calc.Scenario) string {
+	if !scenario.SemiAnnualCompounding {
+		return fmt.Sprintf("%.2f%%", scenario.AnnualRate)
+	}
+	return fmt.Sprintf("%.2f%% compounded semi-annually (%.4f%% effective monthly)", scenario.AnnualRate, scenario.MonthlyRate*100)
+}
 
-		// Calculate monthly rent for this year (12 months)
-		inflatedMonthlyRent := config.monthlyRent * math.Pow(1+config.inflationRate/100, float64(year))
-		ye.monthlyRent = inflatedMonthlyRent * 12
+// formatSVR describes the standard variable rate the loan reverts to after its fixed period
+func formatSVR(scenario *calc.Scenario) string {
+	return fmt.Sprintf("%.2f%% SVR after %s fixed (%s/mo from month %d)", scenario.SVRRate, formatHorizon(scenario.FixedMonths), formatCurrency(scenario.SVRPayment), scenario.FixedMonths+1)
+}
 
-		// Annual rent costs for this year
-		inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(year))
-		ye.rentCosts = inflatedAnnualCost
+// paymentFrequencyName returns the name of the loan payment frequency with the given payments per year
+func paymentFrequencyName(perYear int) string {
+	for name, n := range calc.PaymentFrequencies {
+		if n == perYear {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d per year", perYear)
+}
 
-		ye.total = ye.monthlyRent + ye.rentCosts
-		yearlyData[year] = ye
+// displayCalendarYearInterest shows the loan interest and principal paid in each calendar
+// year, e.g. for the mortgage interest deduction
+func displayCalendarYearInterest(scenario *calc.Scenario) {
+	rows := [][]string{
+		{"Year", "Interest Paid", "Principal Paid"},
 	}
+	for _, year := range scenario.LoanByCalendarYear() {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", year.Year),
+			formatCurrency(year.Interest),
+			formatCurrency(year.Principal),
+		})
+	}
+
+	notes := fmt.Sprintf("Note: Payments start in %s. Partial first and last years only include the months paid in them.", scenario.LoanStart.Format("Jan 2006"))
+	displayTable("LOAN PAYMENTS BY CALENDAR YEAR", rows, notes, false, nil)
+}
+
+// displaySellExpensesBreakdown displays breakdown of rental expenses for SELL scenario
+func displaySellExpensesBreakdown(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows
 	rows := [][]string{
@@ -926,107 +1928,30 @@ func displaySellExpensesBreakdown() {
 
 	// Build each data row
 	for _, period := range periods {
-		// Determine which year this period represents (last year covered by this period)
-		years := (period.months - 1) / 12
-		if years >= len(yearlyData) {
-			years = len(yearlyData) - 1
-		}
-		if years < 0 {
-			years = 0
-		}
-
-		// Get annual expenses for this specific year
-		ye := yearlyData[years]
-
-		// Calculate cumulative total (includes deposit and recoverable)
-		cumulativeTotal := 0.0
-		cumulativeMonthlyRent := 0.0
-		for i := 0; i < period.months; i++ {
-			year := i / 12
-			inflatedMonthlyRent := config.monthlyRent * math.Pow(1+config.inflationRate/100, float64(year))
-			cumulativeMonthlyRent += inflatedMonthlyRent
-		}
-
-		cumulativeAnnualRentCosts := 0.0
-		fullYears := period.months / 12
-		for year := 0; year < fullYears; year++ {
-			inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(year))
-			cumulativeAnnualRentCosts += inflatedAnnualCost
-		}
-		if period.months%12 > 0 {
-			inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(fullYears))
-			cumulativeAnnualRentCosts += inflatedAnnualCost * float64(period.months%12) / 12.0
-		}
-
-		// Cumulative total includes deposit at start and recoverable at end
-		cumulativeTotal = config.rentDeposit + cumulativeMonthlyRent + cumulativeAnnualRentCosts - (config.rentDeposit * 0.75)
+		// Annual expenses for the year this period ends in, plus the cumulative total
+		// (includes deposit and recoverable)
+		expenses := scenario.SellRentExpensesAt(period.months)
 
 		rows = append(rows, []string{
 			"SELL " + period.label,
-			formatCurrency(ye.monthlyRent),
-			formatCurrency(ye.rentCosts),
-			formatCurrency(ye.total),
-			formatCurrency(cumulativeTotal),
+			formatCurrency(expenses.MonthlyRent),
+			formatCurrency(expenses.RentCosts),
+			formatCurrency(expenses.Total),
+			formatCurrency(expenses.CumulativeTotal),
 		})
 	}
 
-	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Monthly Rent' and 'Rent Costs' = Amounts for that year (inflated at %.1f%% annually). 'Total' = Sum for that year. 'Cumulative Total' = Running total including initial deposit (%s) and recoverable deposit (%s at end).",
-		config.inflationRate,
-		formatCurrency(config.rentDeposit),
-		formatCurrency(-config.rentDeposit*0.75))
+	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Monthly Rent' and 'Rent Costs' = Amounts for that year (inflated annually at %s). 'Total' = Sum for that year. 'Cumulative Total' = Running total including initial deposit (%s) and recoverable deposit (%s at end).",
+		formatRateSchedule(scenario.InflationRates, 1),
+		formatCurrency(scenario.RentDeposit),
+		formatCurrency(-scenario.RentDeposit*calc.RecoverableDepositFraction))
 
-	displayTable("SELL EXPENSES BREAKDOWN", rows, noteText, false)
+	displayTable("SELL EXPENSES BREAKDOWN", rows, noteText, false, nil)
 }
 
 // displayKeepExpensesBreakdown displays breakdown of ownership expenses for KEEP scenario
-func displayKeepExpensesBreakdown() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
-
-	// Pre-calculate annual expenses for each year (0-30 years = 372 months to cover year 30 fully)
-	maxMonths := 372
-	type yearlyExpenses struct {
-		loanPayment float64
-		insurance   float64
-		otherCosts  float64 // Other annual costs + monthly expenses
-		total       float64
-	}
-
-	// Calculate expenses for each 12-month period
-	yearlyData := make([]yearlyExpenses, 31) // 0-30 years
-
-	currentInsurance := config.annualInsurance / 12
-	currentOtherCosts := config.annualTaxes / 12
-	currentMonthlyExp := config.monthlyExpenses
-
-	for year := 0; year < 31; year++ {
-		var ye yearlyExpenses
-
-		// Calculate for 12 months of this year
-		for month := 0; month < 12; month++ {
-			monthIndex := year*12 + month
-
-			if monthIndex >= maxMonths {
-				break
-			}
-
-			// Loan payment only during loan term
-			if monthIndex < config.totalMonths {
-				ye.loanPayment += config.monthlyLoanPayment
-			}
-
-			// Recurring expenses
-			ye.insurance += currentInsurance
-			ye.otherCosts += currentOtherCosts + currentMonthlyExp
-		}
-
-		ye.total = ye.loanPayment + ye.insurance + ye.otherCosts
-		yearlyData[year] = ye
-
-		// Apply inflation for next year
-		currentInsurance *= (1 + config.inflationRate/100)
-		currentOtherCosts *= (1 + config.inflationRate/100)
-		currentMonthlyExp *= (1 + config.inflationRate/100)
-	}
+func displayKeepExpensesBreakdown(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows
 	rows := [][]string{
@@ -1035,56 +1960,42 @@ func displayKeepExpensesBreakdown() {
 
 	// Build each data row
 	for _, period := range periods {
-		// Determine which year this period represents (last year covered by this period)
-		years := (period.months - 1) / 12
-		if years >= len(yearlyData) {
-			years = len(yearlyData) - 1
-		}
-		if years < 0 {
-			years = 0
-		}
-
-		// Get annual expenses for this specific year
-		ye := yearlyData[years]
+		// Get annual expenses for the year this period ends in
+		ye := scenario.KeepExpensesAt(period.months)
 
-		// Calculate cumulative expenses and get investment values from pre-calculated arrays
-		cumulativeTotal := 0.0
-		for i := 0; i < period.months; i++ {
-			cumulativeTotal += monthlyBuyingCosts[i]
-		}
-
-		// Get investment value and net position from pre-calculated arrays
-		monthIndex := period.months - 1
-		if monthIndex < 0 {
-			monthIndex = 0
-		}
-		if monthIndex >= len(monthlyKeepInvestmentValue) {
-			monthIndex = len(monthlyKeepInvestmentValue) - 1
-		}
-
-		investmentValue := monthlyKeepInvestmentValue[monthIndex]
-		netPosition := monthlyKeepNetPosition[monthIndex]
+		// Cumulative raw expenses and the investment position of the KEEP scenario
+		cumulativeTotal := scenario.CumulativeBuyingCosts(period.months)
+		position := scenario.KeepPositionAt(period.months)
 
 		rows = append(rows, []string{
 			"KEEP " + period.label,
-			formatCurrency(ye.loanPayment),
-			formatCurrency(ye.insurance),
-			formatCurrency(ye.otherCosts),
+			formatCurrency(ye.LoanPayment),
+			formatCurrency(ye.Insurance),
+			formatCurrency(ye.OtherCosts),
 			formatCurrency(cumulativeTotal),
-			formatCurrency(investmentValue),
-			formatCurrency(netPosition),
+			formatCurrency(position.InvestmentValue),
+			formatCurrency(position.NetPosition),
 		})
 	}
 
-	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Loan Payment' = Loan payments for that year (fixed monthly amount, stops after loan term). 'Tax/Insurance' = Annual tax & insurance (inflated at %.1f%% annually). 'Other Costs' = Other annual costs + monthly expenses (inflated). 'Cumulative Exp' = Running total of raw expenses. 'Investment Val' = Value of invested income (compounded at %.1f%% return). 'Net Position' = Investment value minus real out-of-pocket costs.", config.inflationRate, config.investmentReturnRate)
+	taxGrowth := formatRateSchedule(scenario.InflationRates, 1)
+	if scenario.CapPropertyTax && scenario.PropertyTaxRate <= 0 {
+		taxGrowth = fmt.Sprintf("%.1f%%, the reassessment cap", scenario.TaxReassessmentCap)
+	}
+	if scenario.PropertyTaxRate > 0 {
+		taxGrowth += fmt.Sprintf(", plus property tax at %.2f%% of value", scenario.PropertyTaxRate)
+	}
+	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Loan Payment' = Loan payments for that year (fixed monthly amount, stops after loan term). 'Tax/Insurance' = Annual tax & insurance (inflated annually at %s). 'Other Costs' = Other annual costs + monthly expenses, reserve and utilities (inflated). 'Cumulative Exp' = Running total of raw expenses. 'Investment Val' = Value of invested income (compounded at %s return). 'Net Position' = Investment value minus real out-of-pocket costs.", taxGrowth, formatRateSchedule(scenario.InvestmentReturnRates, 1))
+	if scenario.GroundRent != 0 || scenario.ServiceCharge != 0 {
+		noteText += " 'Other Costs' also includes the ground rent and service charge."
+	}
 
-	displayTable("KEEP EXPENSES BREAKDOWN", rows, noteText, false)
+	displayTable("KEEP EXPENSES BREAKDOWN", rows, noteText, false, nil)
 }
 
 // displayExpenditureTable displays total expenditure for buying vs renting
-// Uses global monthlyBuyingCosts and monthlyRentingCosts arrays
-func displayExpenditureTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+func displayExpenditureTable(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
@@ -1093,159 +2004,328 @@ func displayExpenditureTable() {
 
 	// Add data rows
 	for _, period := range periods {
-		// Calculate total buying expenditure (downpayment + all monthly costs)
-		buyingExpenditure := config.downpayment
-		for i := 0; i < period.months; i++ {
-			buyingExpenditure += monthlyBuyingCosts[i]
-		}
-
-		// Calculate total renting expenditure (deposit + all monthly costs)
-		rentingExpenditure := config.rentDeposit
-		for i := 0; i < period.months; i++ {
-			rentingExpenditure += monthlyRentingCosts[i]
-		}
-
-		difference := buyingExpenditure - rentingExpenditure
+		expenditure := scenario.ExpenditureAt(period.months)
 
 		rows = append(rows, []string{
 			"EXP " + period.label,
-			formatCurrency(buyingExpenditure),
-			formatCurrency(rentingExpenditure),
-			formatCurrency(difference),
+			formatCurrency(expenditure.Buying),
+			formatCurrency(expenditure.Renting),
+			formatCurrency(expenditure.Difference),
 		})
 	}
 
-	notes := fmt.Sprintf("Note: All recurring costs (insurance, taxes, rent, HOA, etc.) are inflated annually at %.1f%% rate.", config.inflationRate)
-	displayTable("TOTAL EXPENDITURE COMPARISON", rows, notes, false)
+	notes := fmt.Sprintf("Note: All recurring costs (insurance, taxes, rent, HOA, etc.) are inflated annually at %s rate.", formatRateSchedule(scenario.InflationRates, 1))
+	if len(scenario.InflationRates) > 1 {
+		notes = fmt.Sprintf("Note: All recurring costs (insurance, taxes, rent, HOA, etc.) are inflated annually on a schedule: %s. Each year's rate raises costs for the following year.", formatRateSchedule(scenario.InflationRates, 1))
+	}
+	if scenario.LeaseBreakPenalty > 0 {
+		notes += fmt.Sprintf(" Renting includes a %s lease-break penalty for periods that end mid-lease (leases run %d months).", formatCurrency(scenario.LeaseBreakPenalty), calc.LeaseMonths)
+	}
+	displayTable("TOTAL EXPENDITURE COMPARISON", rows, notes, false, nil)
 }
 
 // displayComparisonTable displays buy vs rent net worth projections side-by-side
-// Uses global monthlyBuyingCosts and monthlyRentingCosts arrays
-func displayComparisonTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+func displayComparisonTable(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
 		{"Period", "Asset Value", "Buying NW", "Cum Savings", "Market Return", "Renting NW", "RENT - BUY"},
 	}
 
-	// Build each data row
+	// Track the difference of each row to highlight the winning side
+	differences := []float64{0}
+
+	// Build each data row, marking the first one where the other side has taken the lead
+	marked := false
 	for _, period := range periods {
-		assetValue, _, buyingNetWorth := calculateNetWorth(period.months)
+		buying := scenario.NetWorthAt(period.months)
+		renting := scenario.RentingNetWorthAt(period.months)
 
-		rentingNetWorth := calculateRentingNetWorth(period.months)
+		difference := renting.NetWorth - buying.NetWorth
+		differences = append(differences, difference)
 
-		// Calculate cumulative savings (without investment growth)
-		cumulativeSavings := config.downpayment - config.rentDeposit
-		for i := 0; i < period.months; i++ {
-			cumulativeSavings += monthlyBuyingCosts[i] - monthlyRentingCosts[i]
+		label := "NET " + period.label
+		if first := differences[1]; !marked && len(differences) > 2 && ((first > 0 && difference <= 0) || (first < 0 && difference >= 0)) {
+			label += " *"
+			marked = true
 		}
 
-		// Calculate market return (investment growth portion only)
-		recoverableDeposit := config.rentDeposit * 0.75
-		marketReturn := rentingNetWorth - cumulativeSavings - recoverableDeposit
-
-		difference := rentingNetWorth - buyingNetWorth
-
 		rows = append(rows, []string{
-			"NET " + period.label,
-			formatCurrency(assetValue),
-			formatCurrency(buyingNetWorth),
-			formatCurrency(cumulativeSavings),
-			formatCurrency(marketReturn),
-			formatCurrency(rentingNetWorth),
+			label,
+			formatCurrency(buying.AssetValue),
+			formatCurrency(buying.NetWorth),
+			formatCurrency(renting.CumulativeSavings),
+			formatCurrency(renting.MarketReturn),
+			formatCurrency(renting.NetWorth),
 			formatCurrency(difference),
 		})
 	}
 
+	// Optionally reorder data rows by the size of the difference (header stays first)
+	if sortByDifference {
+		order := make([]int, 0, len(rows)-1)
+		for i := 1; i < len(rows); i++ {
+			order = append(order, i)
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return math.Abs(differences[order[a]]) > math.Abs(differences[order[b]])
+		})
+
+		sortedRows := [][]string{rows[0]}
+		sortedDifferences := []float64{0}
+		for _, i := range order {
+			sortedRows = append(sortedRows, rows[i])
+			sortedDifferences = append(sortedDifferences, differences[i])
+		}
+		rows = sortedRows
+		differences = sortedDifferences
+	}
+
+	// On narrow terminals, drop lower-priority columns until the table fits
+	var droppedColumns []string
+	if width := terminalWidth(); width > 0 {
+		for _, header := range []string{"Cum Savings", "Market Return", "Asset Value"} {
+			if tableWidth(rows) <= width {
+				break
+			}
+			rows = dropColumn(rows, header)
+			droppedColumns = append(droppedColumns, "'"+header+"'")
+		}
+	}
+
 	// Build note text with conditional buying NW explanation
-	noteText := fmt.Sprintf("Note: 'Cum Savings' = Cumulative Savings track raw difference in costs (Buying - Renting) without investment growth. See Total Expenditure Comparison.\n\n'Market Return' = investment growth using monthly dollar-cost averaging at %.0f%% annual rate. Each month's savings are invested immediately and compounded monthly. This models realistic investing behavior (not lump sum at year start), so effective return < annual rate for short periods.\n\n'Renting NW' = Cumul. Savings + Market Return + 75%% recoverable deposit. ", config.investmentReturnRate)
-	if config.includeSelling > 0 {
+	noteText := fmt.Sprintf("Note: 'Cum Savings' = Cumulative Savings track raw difference in costs (Buying - Renting) without investment growth. See Total Expenditure Comparison.\n\n'Market Return' = investment growth using monthly dollar-cost averaging at %s annual rate. Each month's savings are invested immediately and compounded monthly. This models realistic investing behavior (not lump sum at year start), so effective return < annual rate for short periods.\n\n'Renting NW' = Cumul. Savings + Market Return + 75%% recoverable deposit. ", formatRateSchedule(scenario.InvestmentReturnRates, 0))
+	if scenario.IncludeSelling {
 		noteText += "'Buying NW' = Net proceeds after selling (sale price - selling costs - loan payoff - taxes). "
 	} else {
 		noteText += "'Buying NW' = Asset value - remaining loan balance. "
 	}
+	if scenario.RebuyYear > 0 {
+		noteText += fmt.Sprintf("After the re-buy at %s, 'Asset Value' and 'Buying NW' are for the new home: the first home's net sale proceeds became its downpayment, the new loan has the same rate and term, and ownership costs scale with the new price. ", formatHorizon(scenario.RebuyYear*12))
+	}
 	noteText += "'RENT - BUY': Positive values mean renting wins, negative values mean buying wins."
+	noteText += " " + crossoverNote(scenario, marked)
+	if sortByDifference {
+		noteText += " Rows are sorted by the size of the difference, largest first."
+	}
+	if len(droppedColumns) > 0 {
+		noteText += fmt.Sprintf(" %s hidden to fit the terminal width.", strings.Join(droppedColumns, ", "))
+	}
+
+	// Color the RENT - BUY cell green when renting wins and red when buying wins
+	highlightWinner := func(row, col int, style lipgloss.Style) lipgloss.Style {
+		if row == 0 || col != len(rows[0])-1 || row >= len(differences) {
+			return style
+		}
+		if differences[row] > 0 {
+			return style.Foreground(activeTheme.Positive)
+		} else if differences[row] < 0 {
+			return style.Foreground(activeTheme.Negative)
+		}
+		return style
+	}
+
+	displayTable("NET WORTH PROJECTIONS: BUY VS RENT", rows, noteText, false, highlightWinner)
+}
 
-	displayTable("NET WORTH PROJECTIONS: BUY VS RENT", rows, noteText, false)
+// crossoverHorizon returns the months the crossover is searched over: the longest period in the
+// tables, which can be the loan term beyond the projection horizon. The comparison table and
+// the verdict share it so they can't disagree.
+func crossoverHorizon(scenario *calc.Scenario) int {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
+	return periods[len(periods)-1].months
 }
 
-// calculateSaleProceeds calculates the net proceeds from selling at a given time
-func calculateSaleProceeds(months int) (salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds float64) {
-	// Determine starting price for appreciation calculation
-	// SELL vs KEEP: start from current market value
-	// BUY vs RENT: start from original purchase price
-	startingPrice := config.purchasePrice
-	if config.currentMarketValue > 0 {
-		startingPrice = config.currentMarketValue
+// describeCrossover describes, from a month-by-month scan over the crossover horizon, the month
+// in which buying and renting swap the lead, or which side stays ahead. It reports whether
+// there is a crossover.
+func describeCrossover(scenario *calc.Scenario) (string, bool) {
+	months := crossoverHorizon(scenario)
+	month, ok := scenario.CrossoverMonth(months)
+	if !ok {
+		leader := "buying"
+		if scenario.RentingNetWorthAt(1).NetWorth-scenario.NetWorthAt(1).NetWorth > 0 {
+			leader = "renting"
+		}
+		return fmt.Sprintf("%s stays ahead through %s", leader, formatHorizon(months)), false
 	}
 
-	// Calculate asset value (sale price) by compounding appreciation rates
-	salePrice = startingPrice
-	years := months / 12
-	remainingMonths := months % 12
+	catchesUp := "buying catches up with renting"
+	if scenario.RentingNetWorthAt(month).NetWorth-scenario.NetWorthAt(month).NetWorth >= 0 {
+		catchesUp = "renting catches up with buying"
+	}
+	return fmt.Sprintf("%s in month %d (%s)", catchesUp, month, formatHorizon(month)), true
+}
 
-	// Apply each year's rate
-	for year := 0; year < years; year++ {
-		rateIndex := year
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
-		}
-		salePrice *= (1 + appreciationRates[rateIndex]/100)
+// crossoverNote names the month in which buying and renting swap the lead
+func crossoverNote(scenario *calc.Scenario, marked bool) string {
+	crossover, ok := describeCrossover(scenario)
+	if !ok {
+		return "No crossover: " + crossover + "."
 	}
+	note := "Crossover: " + crossover + "."
+	if marked {
+		note += " '*' marks the first period after it."
+	}
+	return note
+}
+
+// displayNetWorthBars shows buying vs renting net worth at the projection horizon as bars
+func displayNetWorthBars(scenario *calc.Scenario) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+
+	horizon := projectionHorizon()
+	fmt.Println()
+	fmt.Println(titleStyle.Render(fmt.Sprintf("NET WORTH AT %s", strings.ToUpper(formatHorizon(horizon)))))
+	fmt.Print(drawBars(
+		[]string{"Buying", "Renting"},
+		[]float64{scenario.NetWorthAt(horizon).NetWorth, scenario.RentingNetWorthAt(horizon).NetWorth},
+	))
+}
 
-	// Apply partial year if there are remaining months
-	if remainingMonths > 0 {
-		rateIndex := years
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
+// drawBars renders one horizontal bar per value, scaled to the terminal width (80 columns when
+// it's unknown). Negative values extend left of a zero axis.
+func drawBars(labels []string, values []float64) string {
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	positiveStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	negativeStyle := re.NewStyle().Foreground(activeTheme.Secondary)
+
+	width := terminalWidth()
+	if width <= 0 {
+		width = 80
+	}
+
+	labelWidth, valueWidth := 0, 0
+	valueStrs := make([]string, len(values))
+	maxPositive, maxNegative := 0.0, 0.0
+	for i, value := range values {
+		labelWidth = max(labelWidth, len(labels[i]))
+		valueStrs[i] = formatCurrency(value)
+		valueWidth = max(valueWidth, len(valueStrs[i]))
+		if value > 0 {
+			maxPositive = math.Max(maxPositive, value)
+		} else {
+			maxNegative = math.Max(maxNegative, -value)
 		}
-		partialYearFactor := math.Pow(1+appreciationRates[rateIndex]/100, float64(remainingMonths)/12.0)
-		salePrice *= partialYearFactor
 	}
 
-	// Calculate agent commission
-	agentFee := salePrice * (config.agentCommission / 100)
+	// Split the bar space between the negative and positive sides of the axis
+	barSpace := max(width-labelWidth-valueWidth-6, 10)
+	scale := maxPositive + maxNegative
+	if scale == 0 {
+		scale = 1
+	}
+	negativeSpace := int(math.Round(float64(barSpace) * maxNegative / scale))
+	positiveSpace := barSpace - negativeSpace
+
+	var b strings.Builder
+	for i, value := range values {
+		length := int(math.Round(math.Abs(value) / scale * float64(barSpace)))
+		left := strings.Repeat(" ", negativeSpace)
+		right := strings.Repeat(" ", positiveSpace)
+		if value < 0 {
+			left = strings.Repeat(" ", negativeSpace-length) + negativeStyle.Render(strings.Repeat("█", length))
+		} else {
+			right = positiveStyle.Render(strings.Repeat("█", length)) + strings.Repeat(" ", positiveSpace-length)
+		}
+
+		axis := ""
+		if maxNegative > 0 {
+			axis = "│"
+		}
+		fmt.Fprintf(&b, "  %s %s%s%s %*s\n", labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, labels[i])), left, axis, right, valueWidth, valueStrs[i])
+	}
+	return b.String()
+}
 
-	// Combine agent commission and staging costs
-	totalSellingCosts = agentFee + config.stagingCosts
+// projectionHorizon returns the furthest standard projection period in months
+// (10 years, 30 years with include30Year, or the longest custom period)
+func projectionHorizon() int {
+	if len(customPeriods) > 0 {
+		return customPeriods[len(customPeriods)-1]
+	}
+	if include30Year {
+		return 360
+	}
+	return 120
+}
 
-	// Get remaining loan balance
-	monthIndex := months - 1
-	if monthIndex >= len(remainingLoanBalance) {
-		monthIndex = len(remainingLoanBalance) - 1
+// summarizeVerdict returns a plain-language bottom line for BUY vs RENT at the given horizon,
+// along with the breakeven: the crossover the comparison table notes
+func summarizeVerdict(scenario *calc.Scenario, horizonMonths int) []string {
+	horizonLabel := fmt.Sprintf("%d years", horizonMonths/12)
+	if horizonMonths%12 != 0 || horizonMonths < 12 {
+		horizonLabel = strings.TrimSpace(formatPeriodLabel(horizonMonths))
 	}
-	loanPayoff = remainingLoanBalance[monthIndex]
 
-	// Calculate capital gains (selling costs are deductible)
-	capitalGains = salePrice - config.purchasePrice - totalSellingCosts
+	difference := scenario.RentingNetWorthAt(horizonMonths).NetWorth - scenario.NetWorthAt(horizonMonths).NetWorth
 
-	// Get tax-free limit for this period
-	// For year 1 (12 months), use index 0; for year 2 (24 months), use index 1, etc.
-	taxFreeLimitIndex := years - 1
-	if taxFreeLimitIndex < 0 {
-		taxFreeLimitIndex = 0
+	var lines []string
+	if difference > 0 {
+		lines = append(lines, fmt.Sprintf("Over %s, renting + investing leaves you %s ahead of buying.", horizonLabel, formatCurrency(difference)))
+	} else if difference < 0 {
+		lines = append(lines, fmt.Sprintf("Over %s, buying wins by %s over renting + investing.", horizonLabel, formatCurrency(-difference)))
+	} else {
+		lines = append(lines, fmt.Sprintf("Over %s, buying and renting + investing end up even.", horizonLabel))
 	}
-	if taxFreeLimitIndex >= len(taxFreeLimits) {
-		taxFreeLimitIndex = len(taxFreeLimits) - 1
+
+	crossover, _ := describeCrossover(scenario)
+	lines = append(lines, "Breakeven: "+crossover+".")
+	return lines
+}
+
+// summarizeIRR returns the buying IRR at the given horizon, for comparing against other investments
+func summarizeIRR(scenario *calc.Scenario, horizonMonths int) string {
+	irr, ok := scenario.BuyingIRR(horizonMonths)
+	if !ok {
+		return "Buying IRR: n/a (the cash flows have no rate of return)."
 	}
-	taxFreeLimit := taxFreeLimits[taxFreeLimitIndex]
+	return fmt.Sprintf("Buying IRR: %.1f%%/yr, vs. %s assumed for investments.", irr, formatRateSchedule(scenario.InvestmentReturnRates, 1))
+}
 
-	// Calculate taxable gains (after exemption)
-	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
+// displayNPVComparison prints the net present value of buying and renting at the projection horizon
+func displayNPVComparison(scenario *calc.Scenario, rate float64) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	textStyle := re.NewStyle().Foreground(activeTheme.Text)
 
-	// Calculate tax on gains
-	taxOnGains = taxableGains * (config.capitalGainsTax / 100)
+	horizon := projectionHorizon()
+	npv := scenario.NPVAt(horizon, rate)
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render(fmt.Sprintf("NET PRESENT VALUE (%.1f%% discount rate, %s)", rate, formatHorizon(horizon))))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Buying NPV"), formatCurrency(npv.Buying))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Renting NPV"), formatCurrency(npv.Renting))
+	if npv.Difference > 0 {
+		fmt.Println("  " + textStyle.Render(fmt.Sprintf("Renting comes out %s ahead in today's dollars.", formatCurrency(npv.Difference))))
+	} else if npv.Difference < 0 {
+		fmt.Println("  " + textStyle.Render(fmt.Sprintf("Buying comes out %s ahead in today's dollars.", formatCurrency(-npv.Difference))))
+	} else {
+		fmt.Println("  " + textStyle.Render("Buying and renting are even in today's dollars."))
+	}
+}
 
-	// Calculate net proceeds
-	netProceeds = salePrice - totalSellingCosts - loanPayoff - taxOnGains
+// displayVerdict prints the plain-language verdict after the projection tables
+func displayVerdict(scenario *calc.Scenario) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	textStyle := re.NewStyle().Foreground(activeTheme.Text)
 
-	return
+	fmt.Println()
+	fmt.Println(titleStyle.Render("VERDICT"))
+	for _, line := range summarizeVerdict(scenario, projectionHorizon()) {
+		fmt.Println("  " + textStyle.Render(line))
+	}
+	fmt.Println("  " + textStyle.Render(summarizeIRR(scenario, projectionHorizon())))
 }
 
 // displaySaleProceeds displays the proceeds from selling the property at various periods
-func displaySaleProceeds() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+func displaySaleProceeds(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
@@ -1254,26 +2334,123 @@ func displaySaleProceeds() {
 
 	// Build each data row
 	for _, period := range periods {
-		salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds := calculateSaleProceeds(period.months)
+		sale := scenario.SaleProceedsAt(period.months)
 
 		rows = append(rows, []string{
 			"SALE " + period.label,
-			formatCurrency(salePrice),
-			formatCurrency(totalSellingCosts),
-			formatCurrency(loanPayoff),
-			formatCurrency(capitalGains),
-			formatCurrency(taxOnGains),
-			formatCurrency(netProceeds),
+			formatCurrency(sale.SalePrice),
+			formatCurrency(sale.SellingCosts),
+			formatCurrency(sale.LoanPayoff),
+			formatCurrency(sale.CapitalGains),
+			formatCurrency(sale.Tax),
+			formatCurrency(sale.NetProceeds),
 		})
 	}
 
 	notes := "Note: Appreciation rates are applied year-by-year (compounded). If multiple rates are specified (e.g., '-20,-10,-5'), first rate applies to year 1, second to year 2, etc. The last rate applies to all remaining years. Sale price = compounded property value."
-	displayTable("SALE PROCEEDS ANALYSIS", rows, notes, false)
+	if scenario.AppreciationInDollars {
+		notes = "Note: Appreciation is a fixed dollar amount added each year (not compounded), prorated for partial years. If multiple amounts are specified (e.g., '20K,10K,5K'), first amount applies to year 1, second to year 2, etc. The last amount applies to all remaining years. Sale price = starting value plus the yearly amounts."
+	}
+	if scenario.InvestmentProperty {
+		notes += fmt.Sprintf(" As an investment property, 'Tax' includes %.0f%% recapture of the depreciation taken (%s/yr).", calc.DepreciationRecaptureRate, formatCurrency(scenario.MonthlyDepreciation()*12))
+	}
+	if improvements := formatImprovements(scenario.CapitalImprovements); improvements != "" {
+		notes += fmt.Sprintf(" Capital improvements (%s) are added to the value when made and raise the cost basis, reducing 'Cap Gains'.", improvements)
+	}
+	if scenario.StagingCosts > 0 {
+		notes += fmt.Sprintf(" 'Selling Cost' = agent commission plus staging costs (%s today, inflated at %s).", formatCurrency(scenario.StagingCosts), formatRateSchedule(scenario.InflationRates, 1))
+	}
+	displayTable("SALE PROCEEDS ANALYSIS", rows, notes, false, nil)
+
+	if scenario.CurrentMarketValue == 0 {
+		displayMonthsToWhole(scenario)
+	}
+}
+
+// displayAffordability shows the front-end debt-to-income ratio, with a warning when the
+// housing cost alone is past the usual lending guidelines
+func displayAffordability(scenario *calc.Scenario, labelStyle lipgloss.Style) {
+	dti := scenario.FrontEndDTI()
+	var note string
+	switch {
+	case dti > calc.BackEndDTILimit:
+		note = fmt.Sprintf("WARNING: above even the %.0f%% guideline for all debt payments", calc.BackEndDTILimit)
+	case dti > calc.FrontEndDTILimit:
+		note = fmt.Sprintf("WARNING: above the %.0f%% guideline for housing costs", calc.FrontEndDTILimit)
+	default:
+		note = fmt.Sprintf("within the %.0f%% guideline for housing costs", calc.FrontEndDTILimit)
+	}
+	fmt.Printf("  %s: %.1f%% of %s/mo gross income (%s)\n", labelStyle.Render("Debt-to-Income (front-end)"), dti, formatCurrency(scenario.GrossMonthlyIncome), note)
+}
+
+// displayInvestmentMetrics shows the investment property's first-year screening metrics: the
+// gross rent multiplier and the cap rate
+func displayInvestmentMetrics(scenario *calc.Scenario) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+
+	metrics := scenario.InvestmentMetrics()
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("INVESTMENT METRICS (year 1)"))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Gross Rental Income"), formatCurrency(metrics.AnnualRent))
+	fmt.Printf("  %s: %s (ownership costs, excluding the loan)\n", labelStyle.Render("Operating Expenses"), formatCurrency(metrics.OperatingExpenses))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Net Operating Income"), formatCurrency(metrics.NetOperatingIncome))
+	if metrics.AnnualRent > 0 {
+		fmt.Printf("  %s: %.1fx (purchase price / annual rent)\n", labelStyle.Render("Gross Rent Multiplier"), metrics.GrossRentMultiplier)
+	} else {
+		fmt.Printf("  %s: n/a (no rental income)\n", labelStyle.Render("Gross Rent Multiplier"))
+	}
+	if scenario.PurchasePrice > 0 {
+		fmt.Printf("  %s: %.2f%% (net operating income / purchase price)\n", labelStyle.Render("Cap Rate"), metrics.CapRate)
+	} else {
+		fmt.Printf("  %s: n/a (no purchase price)\n", labelStyle.Render("Cap Rate"))
+	}
+}
+
+// displayCashOnCash shows the investment property's yearly rental cash flow over the cash invested
+func displayCashOnCash(scenario *calc.Scenario) {
+	rows := [][]string{{"Year", "Rental Income", "Operating Costs", "Net Cash Flow", "Cash Invested", "Cash-on-Cash"}}
+	for _, year := range scenario.CashOnCashByYear() {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", year.Year),
+			formatCurrency(year.Income),
+			formatCurrency(year.Income - year.CashFlow),
+			formatCurrency(year.CashFlow),
+			formatCurrency(year.CashInvested),
+			fmt.Sprintf("%.2f%%", year.Return),
+		})
+	}
+	if len(rows) == 1 {
+		return
+	}
+
+	notes := "Note: Cash-on-cash = net rental cash flow / cash invested. Operating costs are the loan payment and recurring ownership costs, before income tax and the depreciation shield. Cash invested is the downpayment, purchase transfer tax, and any capital improvements or recast lump sum paid so far."
+	if scenario.RebuyYear > 0 {
+		notes += " Rental income stops at the re-buy."
+	}
+	displayTable("CASH-ON-CASH RETURN", rows, notes, false, nil)
+}
+
+// displayMonthsToWhole shows when selling first returns the cash put into the home, net of
+// selling costs and taxes: the point at which the buyer is no longer underwater
+func displayMonthsToWhole(scenario *calc.Scenario) {
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+
+	label := labelStyle.Render("Sell & Walk Away Whole")
+	months, ok := scenario.MonthsToWhole()
+	if !ok {
+		fmt.Printf("  %s: not within %s; net sale proceeds stay below the cash put in\n", label, formatHorizon(scenario.Months()))
+		return
+	}
+	fmt.Printf("  %s: after %s (month %d), when net sale proceeds (%s) first cover the cash put in (%s: downpayment, principal and improvements)\n",
+		label, formatHorizon(months), months, formatCurrency(scenario.SaleProceedsAt(months).NetProceeds), formatCurrency(scenario.CashInvestedAt(months)))
 }
 
 // displayNetWorthTable displays net worth projections in a table format
-// Uses global monthlyBuyingCosts array
-func displayNetWorthTable(purchasePrice, downpayment float64, loanDuration int, includeSelling float64,
+func displayNetWorthTable(scenario *calc.Scenario, purchasePrice, downpayment float64, loanDuration int, includeSelling float64,
 	agentCommission, stagingCosts, taxFreeLimit, capitalGainsTax float64) {
 	// Define standard periods
 	standardPeriods := []struct {
@@ -1338,299 +2515,167 @@ func displayNetWorthTable(purchasePrice, downpayment float64, loanDuration int,
 
 	// Print each row
 	for _, period := range periods {
-		assetValue, totalExpenditure, netWorth := calculateNetWorth(period.months)
+		netWorth := scenario.NetWorthAt(period.months)
 
 		fmt.Printf("%-20s %-20s %-20s %-20s\n",
 			period.label,
-			formatCurrency(assetValue),
-			formatCurrency(totalExpenditure),
-			formatCurrency(netWorth),
+			formatCurrency(netWorth.AssetValue),
+			formatCurrency(netWorth.TotalExpenditure),
+			formatCurrency(netWorth.NetWorth),
 		)
 	}
 }
 
-// calculateNetWorth calculates the asset value, total expenditure, and net worth for a given time period
-// Uses the global monthlyBuyingCosts and remainingLoanBalance arrays
-func calculateNetWorth(months int) (float64, float64, float64) {
-	// Calculate asset value by compounding each year's appreciation rate
-	assetValue := config.purchasePrice
-	years := months / 12
-	remainingMonths := months % 12
-
-	// Apply each year's rate
-	for year := 0; year < years; year++ {
-		rateIndex := year
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1 // Use last rate for all future years
-		}
-		assetValue *= (1 + appreciationRates[rateIndex]/100)
-	}
-
-	// Apply partial year if there are remaining months
-	if remainingMonths > 0 {
-		rateIndex := years
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
-		}
-		partialYearFactor := math.Pow(1+appreciationRates[rateIndex]/100, float64(remainingMonths)/12.0)
-		assetValue *= partialYearFactor
-	}
-
-	// Calculate total expenditure by summing monthly costs from array
-	totalExpenditure := config.downpayment
-	for i := 0; i < months; i++ {
-		totalExpenditure += monthlyBuyingCosts[i]
-	}
-
-	// Calculate net worth
-	var netWorth float64
-	if config.includeSelling > 0 {
-		// If selling is enabled, use net proceeds after selling costs
-		_, _, _, _, _, netProceeds := calculateSaleProceeds(months)
-		netWorth = netProceeds
-	} else {
-		// Otherwise, just asset value minus loan balance
-		monthIndex := months - 1
-		if monthIndex >= len(remainingLoanBalance) {
-			monthIndex = len(remainingLoanBalance) - 1
-		}
-		loanBalance := remainingLoanBalance[monthIndex]
-		netWorth = assetValue - loanBalance
-	}
-
-	return assetValue, totalExpenditure, netWorth
-}
-
-// populateMonthlyCosts fills global arrays with monthly costs for buying and renting
-// Uses global config struct for all parameters
-func populateMonthlyCosts() {
-	maxMonths := 360 // 30 years maximum projection
-
-	monthlyBuyingCosts = make([]float64, maxMonths)
-	monthlyRentingCosts = make([]float64, maxMonths)
-	remainingLoanBalance = make([]float64, maxMonths)
-	cumulativePrincipalPaid = make([]float64, maxMonths)
-	cumulativeInterestPaid = make([]float64, maxMonths)
-
-	// Calculate monthly recurring expenses from config
-	totalAnnualExpenses := config.annualInsurance + config.annualTaxes
-	monthlyRecurringExpenses := (totalAnnualExpenses / 12) + config.monthlyExpenses
-
-	// Calculate current rental cost with annual increases
-	currentRentingCost := config.totalMonthlyRentingCost
-
-	// Track current recurring expenses (will increase with inflation)
-	currentRecurringExpenses := monthlyRecurringExpenses
-
-	// Track remaining loan balance
-	currentBalance := config.loanAmount
-	totalPrincipalPaid := 0.0
-	totalInterestPaid := 0.0
-
-	for i := 0; i < maxMonths; i++ {
-		// Apply inflation to all costs at the start of each year (except the first month)
-		if i > 0 && i%12 == 0 {
-			currentRentingCost *= (1 + config.inflationRate/100)
-			currentRecurringExpenses *= (1 + config.inflationRate/100)
-		}
-
-		// Set renting cost for this month
-		monthlyRentingCosts[i] = currentRentingCost
-
-		// Buying cost: loan payment stops after loan duration, but recurring expenses continue
-		if i < config.totalMonths {
-			monthlyBuyingCosts[i] = config.monthlyLoanPayment + currentRecurringExpenses
-
-			// Calculate interest for this month
-			interestPayment := currentBalance * config.monthlyRate
-			// Principal payment is the remainder
-			principalPayment := config.monthlyLoanPayment - interestPayment
-			// Reduce the balance
-			currentBalance -= principalPayment
-
-			// Track cumulative amounts
-			totalPrincipalPaid += principalPayment
-			totalInterestPaid += interestPayment
-
-			// Store remaining balance after this payment
-			remainingLoanBalance[i] = currentBalance
-			cumulativePrincipalPaid[i] = totalPrincipalPaid
-			cumulativeInterestPaid[i] = totalInterestPaid
-		} else {
-			// After loan is paid off, only recurring expenses remain
-			monthlyBuyingCosts[i] = currentRecurringExpenses
-			remainingLoanBalance[i] = 0
-			cumulativePrincipalPaid[i] = totalPrincipalPaid
-			cumulativeInterestPaid[i] = totalInterestPaid
-		}
-	}
-
-	// Calculate KEEP investment tracking arrays
-	calculateKeepInvestmentTracking(maxMonths)
-}
-
-// calculateKeepInvestmentTracking populates investment tracking arrays for KEEP scenario
-func calculateKeepInvestmentTracking(maxMonths int) {
-	monthlyKeepInvestmentValue = make([]float64, maxMonths)
-	monthlyKeepRealCosts = make([]float64, maxMonths)
-	monthlyKeepNetPosition = make([]float64, maxMonths)
-
-	investmentValue := 0.0
-	totalRealCosts := 0.0
-	monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-	for i := 0; i < maxMonths; i++ {
-		monthlyCost := monthlyBuyingCosts[i]
-
-		if monthlyCost < 0 {
-			// Income: invest it
-			investmentValue += -monthlyCost
-		} else if monthlyCost > 0 {
-			// Expense: first use investment value, then real costs
-			if investmentValue >= monthlyCost {
-				investmentValue -= monthlyCost
-			} else {
-				// Use up all investment, remainder is real cost
-				deficit := monthlyCost - investmentValue
-				investmentValue = 0
-				totalRealCosts += deficit
-			}
-		}
-
-		// Compound whatever investment value remains
-		investmentValue *= (1 + monthlyInvestmentRate)
-
-		// Store values for this month
-		monthlyKeepInvestmentValue[i] = investmentValue
-		monthlyKeepRealCosts[i] = totalRealCosts
-		monthlyKeepNetPosition[i] = investmentValue - totalRealCosts
-	}
-}
-
-// calculateRentingNetWorth calculates net worth for the renting scenario
-// Uses month-by-month calculation: investment grows from downpayment + monthly savings
-func calculateRentingNetWorth(months int) float64 {
-	// Start with downpayment minus deposit as initial investment
-	investmentValue := config.downpayment - config.rentDeposit
-	monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-	// For each month: calculate savings, add to investment, grow investment
-	for i := 0; i < months; i++ {
-		// Monthly savings = buying cost - renting cost
-		monthlySavings := monthlyBuyingCosts[i] - monthlyRentingCosts[i]
-
-		// Add savings to investment
-		investmentValue += monthlySavings
-
-		// Apply monthly growth
-		investmentValue *= (1 + monthlyInvestmentRate)
-	}
-
-	// Add back 75% of deposit (recoverable)
-	recoverableDeposit := config.rentDeposit * 0.75
-
-	return investmentValue + recoverableDeposit
-}
-
 // displayInputParametersSellVsKeep displays input parameters for SELL vs KEEP scenario
-func displayInputParametersSellVsKeep(md *MarketData) {
-	re := lipgloss.NewRenderer(os.Stdout)
-	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
-	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
-	groupStyle := re.NewStyle().Foreground(MonokaiOrange).Bold(true)
+func displayInputParametersSellVsKeep(scenario *calc.Scenario, md *MarketData) {
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	groupStyle := re.NewStyle().Foreground(activeTheme.Secondary).Bold(true)
 
 	fmt.Println()
 	fmt.Println(titleStyle.Render("INPUT PARAMETERS - SELL VS KEEP"))
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ECONOMIC ASSUMPTIONS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Inflation Rate"), config.inflationRate)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Investment Return Rate"), config.investmentReturnRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Inflation Rate"), formatRateSchedule(scenario.InflationRates, 2))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Investment Return Rate"), formatRateSchedule(scenario.InvestmentReturnRates, 2))
+	if scenario.InvestmentFee != 0 {
+		fmt.Printf("  %s: %.2f%% per year (net return %s)\n", labelStyle.Render("Investment Fees"), scenario.InvestmentFee, formatRateSchedule(scenario.NetInvestmentReturnRates(), 2))
+	}
 
 	// Display market averages with ticker symbols in cyan
 	if md != nil && len(md.VOO) > 0 {
-		vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(md)
-		if vooAvg > 0 {
-			tickerStyle := re.NewStyle().Foreground(MonokaiCyan)
+		avg := calculateMarketAverages(md)
+		if avg.VOO > 0 {
+			tickerStyle := re.NewStyle().Foreground(activeTheme.Accent)
 			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%\n",
-				tickerStyle.Render("VOO"), vooAvg,
-				tickerStyle.Render("QQQ"), qqqAvg,
-				tickerStyle.Render("VTI"), vtiAvg,
-				tickerStyle.Render("BND"), bndAvg,
-				tickerStyle.Render("60/40"), mix6040Avg)
+				tickerStyle.Render("VOO"), avg.VOO,
+				tickerStyle.Render("QQQ"), avg.QQQ,
+				tickerStyle.Render("VTI"), avg.VTI,
+				tickerStyle.Render("BND"), avg.BND,
+				tickerStyle.Render("60/40"), avg.Mix6040)
 		}
 	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ASSET"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Original Purchase Price"), formatCurrency(config.purchasePrice))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Market Value"), formatCurrency(config.currentMarketValue))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Equity"), formatCurrency(config.downpayment))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Original Purchase Price"), formatCurrency(scenario.PurchasePrice))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Market Value"), formatCurrency(scenario.CurrentMarketValue))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Equity"), formatCurrency(scenario.Downpayment))
 
-	if config.loanAmount > 0 {
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Balance"), formatCurrency(config.loanAmount))
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.annualRate)
+	if scenario.LoanAmount > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Balance"), formatCurrency(scenario.LoanAmount))
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Rate"), formatLoanRate(scenario))
 		loanDurationStr := ""
-		if config.totalMonths%12 == 0 {
-			loanDurationStr = fmt.Sprintf("%dy", config.totalMonths/12)
+		if scenario.LoanMonths%12 == 0 {
+			loanDurationStr = fmt.Sprintf("%dy", scenario.LoanMonths/12)
 		} else {
-			loanDurationStr = fmt.Sprintf("%d months", config.totalMonths)
+			loanDurationStr = fmt.Sprintf("%d months", scenario.LoanMonths)
 		}
 		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Term"), loanDurationStr)
+		if scenario.SVRPayment > 0 {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Rate After Fixed Period"), formatSVR(scenario))
+		}
+		if scenario.OffsetSavings > 0 {
+			fmt.Printf("  %s: %s (no interest on that much of the balance)\n", labelStyle.Render("Offset Savings"), formatCurrency(scenario.OffsetSavings))
+		}
 	} else {
 		fmt.Printf("  %s: Fully paid off\n", labelStyle.Render("Loan Status"))
 	}
 
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.annualInsurance))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.annualTaxes))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.monthlyExpenses))
+	if scenario.PropertyTaxRate > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Insurance"), formatCurrency(scenario.AnnualInsurance))
+		fmt.Printf("  %s: %.2f%% of value (%s in year 1)\n", labelStyle.Render("Property Tax"), scenario.PropertyTaxRate, formatCurrency(scenario.PropertyTaxAt(0)))
+	} else {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(scenario.AnnualInsurance))
+	}
+	if scenario.CapPropertyTax {
+		fmt.Printf("  %s: %.2f%%/yr (%s growth)\n", labelStyle.Render("Tax Reassessment Cap"), scenario.TaxReassessmentCap, taxCapTarget(scenario))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(scenario.AnnualTaxes))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(scenario.MonthlyExpenses))
+	if scenario.ReplacementReserve != 0 {
+		fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Replacement Reserve"), formatCurrency(scenario.ReplacementReserve))
+	}
+	if scenario.BuyUtilities != 0 {
+		fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Utilities"), formatCurrency(scenario.BuyUtilities))
+	}
+	displayLeaseholdCharges(scenario, labelStyle)
+	displayCouncilTax(scenario, labelStyle)
 
 	// Format appreciation rates
 	appreciationRateStr := ""
-	if len(appreciationRates) == 1 {
-		appreciationRateStr = fmt.Sprintf("%.2f%% (all years)", appreciationRates[0])
+	if len(scenario.AppreciationRates) == 1 {
+		appreciationRateStr = fmt.Sprintf("%s (all years)", formatAppreciation(scenario, scenario.AppreciationRates[0]))
 	} else {
-		rateStrs := make([]string, len(appreciationRates))
-		for i, rate := range appreciationRates {
-			if i == len(appreciationRates)-1 {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d+)", rate, i+1)
-			} else {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d)", rate, i+1)
-			}
-		}
-		appreciationRateStr = strings.Join(rateStrs, ", ")
+		appreciationRateStr = formatYearSchedule(scenario.AppreciationRates, func(rate float64) string {
+			return formatAppreciation(scenario, rate)
+		})
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Appreciation Rate (if keeping)"), appreciationRateStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost (if keeping)"), formatCurrency(config.totalMonthlyBuyingCost))
+	if improvements := formatImprovements(scenario.CapitalImprovements); improvements != "" {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Capital Improvements (if keeping)"), improvements)
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost (if keeping)"), formatCurrency(scenario.TotalMonthlyBuyingCost))
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("INVESTING (if selling)"))
 
 	// Check if renting analysis is included
-	includeRenting, _ := getFloatValue("include_renting_sell")
-	if includeRenting > 0 {
+	if scenario.IncludeRenting {
 		fmt.Printf("  %s: Yes\n", labelStyle.Render("Include Renting Analysis"))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.rentDeposit))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.monthlyRent))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.annualRentCosts))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Renting Cost"), formatCurrency(config.totalMonthlyRentingCost))
+		if scenario.DepositEarnsReturns {
+			fmt.Printf("  %s: %s (recoverable %.0f%% grows at the investment return)\n", labelStyle.Render("Rental Deposit"), formatCurrency(scenario.RentDeposit), calc.RecoverableDepositFraction*100)
+		} else {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(scenario.RentDeposit))
+		}
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(scenario.MonthlyRent))
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(scenario.AnnualRentCosts))
+		if scenario.RentUtilities != 0 {
+			fmt.Printf("  %s: %s/mo\n", labelStyle.Render("Utilities"), formatCurrency(scenario.RentUtilities))
+		}
+		if scenario.MoveCost > 0 && scenario.MoveEveryMonths > 0 {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Moving Costs"), formatMoveSchedule(scenario))
+		}
+		if scenario.LeaseBreakPenalty > 0 {
+			fmt.Printf("  %s: %s (if leaving mid-lease)\n", labelStyle.Render("Lease Break Penalty"), formatCurrency(scenario.LeaseBreakPenalty))
+		}
+		if scenario.CapRentIncrease {
+			fmt.Printf("  %s: %.2f%%/yr\n", labelStyle.Render("Rent Increase Cap"), scenario.RentIncreaseCap)
+		}
+		if scenario.RentFreeMonths > 0 {
+			fmt.Printf("  %s: %d\n", labelStyle.Render("Rent-Free Months"), scenario.RentFreeMonths)
+		}
+		if scenario.RentUpfrontMonths > 0 {
+			fmt.Printf("  %s: %d (%s at move-in)\n", labelStyle.Render("Rent Paid Upfront (months)"), scenario.RentUpfrontMonths, formatCurrency(scenario.UpfrontRent()))
+		}
+		if scenario.BrokerFee > 0 && scenario.MonthlyRent > 0 {
+			fmt.Printf("  %s: %s (%.1f%% of annual rent)\n", labelStyle.Render("Broker Fee"), formatCurrency(scenario.BrokerFee), scenario.BrokerFee/(scenario.MonthlyRent*12)*100)
+		} else if scenario.BrokerFee > 0 {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Broker Fee"), formatCurrency(scenario.BrokerFee))
+		}
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Renting Cost"), formatCurrency(scenario.TotalMonthlyRentingCost))
 	} else {
 		fmt.Printf("  %s: No\n", labelStyle.Render("Include Renting Analysis"))
 	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("SELLING COSTS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.agentCommission)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.stagingCosts))
+	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), scenario.AgentCommission)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(scenario.StagingCosts))
+	if scenario.TransferTaxOnSale && scenario.TransferTaxPct > 0 {
+		fmt.Printf("  %s: %.2f%% of sale price\n", labelStyle.Render("Transfer Tax"), scenario.TransferTaxPct)
+	}
 
 	// Format tax-free limits
 	taxFreeLimitStr := ""
-	if len(taxFreeLimits) == 1 {
-		taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(taxFreeLimits[0]))
+	if len(scenario.TaxFreeLimits) == 1 {
+		taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(scenario.TaxFreeLimits[0]))
 	} else {
-		limitStrs := make([]string, len(taxFreeLimits))
-		for i, limit := range taxFreeLimits {
-			if i == len(taxFreeLimits)-1 {
+		limitStrs := make([]string, len(scenario.TaxFreeLimits))
+		for i, limit := range scenario.TaxFreeLimits {
+			if i == len(scenario.TaxFreeLimits)-1 {
 				limitStrs[i] = fmt.Sprintf("%s (year %d+)", formatCurrency(limit), i+1)
 			} else {
 				limitStrs[i] = fmt.Sprintf("%s (year %d)", formatCurrency(limit), i+1)
@@ -1639,87 +2684,16 @@ func displayInputParametersSellVsKeep(md *MarketData) {
 		taxFreeLimitStr = strings.Join(limitStrs, ", ")
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Tax-Free Gains Limit"), taxFreeLimitStr)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.capitalGainsTax)
-}
-
-// calculateSellNetWorth calculates net worth if selling at month 0 and investing proceeds
-func calculateSellNetWorth(months int) float64 {
-	// Calculate net proceeds from selling now
-	salePrice := config.currentMarketValue
-	agentFee := salePrice * (config.agentCommission / 100)
-	totalSellingCosts := agentFee + config.stagingCosts
-	loanPayoff := config.loanAmount
-	// Capital gains with selling costs deducted
-	capitalGains := salePrice - config.purchasePrice - totalSellingCosts
-	// Use first tax-free limit (selling now = year 0)
-	taxFreeLimit := taxFreeLimits[0]
-	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
-	taxOnGains := taxableGains * (config.capitalGainsTax / 100)
-	netProceeds := salePrice - totalSellingCosts - loanPayoff - taxOnGains
-
-	// Check if we need to account for renting
-	includeRenting, _ := getFloatValue("include_renting_sell")
-
-	if includeRenting > 0 {
-		// Start investment with net proceeds minus rental deposit
-		investmentValue := netProceeds - config.rentDeposit
-		monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-		// For each month: subtract rental costs, grow investment
-		for i := 0; i < months; i++ {
-			// Subtract renting costs
-			investmentValue -= monthlyRentingCosts[i]
-
-			// Apply monthly growth
-			investmentValue *= (1 + monthlyInvestmentRate)
-		}
-
-		// Add back 75% of rental deposit (recoverable)
-		recoverableDeposit := config.rentDeposit * 0.75
-		return investmentValue + recoverableDeposit
-	} else {
-		// Just invest the proceeds without rental costs
-		investmentValue := netProceeds
-		monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-		// Simple monthly compounding
-		for i := 0; i < months; i++ {
-			investmentValue *= (1 + monthlyInvestmentRate)
-		}
-
-		return investmentValue
-	}
-}
-
-// calculateKeepNetWorth calculates net worth if keeping the asset and selling at future point
-func calculateKeepNetWorth(months int) float64 {
-	// Get net proceeds from selling at this future point
-	// This accounts for appreciation, selling costs, loan payoff, and capital gains tax
-	_, _, _, _, _, netProceeds := calculateSaleProceeds(months)
-
-	// Get net position from pre-calculated arrays
-	monthIndex := months - 1
-	if monthIndex < 0 {
-		monthIndex = 0
-	}
-	if monthIndex >= len(monthlyKeepNetPosition) {
-		monthIndex = len(monthlyKeepNetPosition) - 1
-	}
-	netPosition := monthlyKeepNetPosition[monthIndex]
-
-	return netProceeds + netPosition
+	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), scenario.CapitalGainsTax)
 }
 
 // displaySellVsKeepComparison displays the comparison table for SELL vs KEEP
-func displaySellVsKeepComparison() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
-
-	// Check if renting analysis is included
-	includeRenting, _ := getFloatValue("include_renting_sell")
+func displaySellVsKeepComparison(scenario *calc.Scenario) {
+	periods := getPeriods(scenario.LoanMonths, include30Year)
 
 	// Build table rows with Cum. Expenses columns
 	var rows [][]string
-	if includeRenting > 0 {
+	if scenario.IncludeRenting {
 		rows = [][]string{
 			{"Period", "SELL Cum. Exp", "SELL Net Worth", "KEEP Net Position", "KEEP Net Proceeds", "KEEP - SELL"},
 		}
@@ -1731,28 +2705,16 @@ func displaySellVsKeepComparison() {
 
 	// Build each data row
 	for _, period := range periods {
-		sellNetWorth := calculateSellNetWorth(period.months)
-		keepNetWorth := calculateKeepNetWorth(period.months)
+		sellNetWorth := scenario.SellNetWorthAt(period.months)
+		keepNetWorth := scenario.KeepNetWorthAt(period.months)
 		difference := keepNetWorth - sellNetWorth
 
-		// Get net position for KEEP from pre-calculated arrays
-		monthIndex := period.months - 1
-		if monthIndex < 0 {
-			monthIndex = 0
-		}
-		if monthIndex >= len(monthlyKeepNetPosition) {
-			monthIndex = len(monthlyKeepNetPosition) - 1
-		}
-		keepNetPosition := monthlyKeepNetPosition[monthIndex]
+		// Get net position for KEEP
+		keepNetPosition := scenario.KeepPositionAt(period.months).NetPosition
 
-		if includeRenting > 0 {
-			// Calculate cumulative rental expenses for SELL
-			cumulativeRentExpenses := config.rentDeposit // Initial deposit
-			for i := 0; i < period.months; i++ {
-				cumulativeRentExpenses += monthlyRentingCosts[i]
-			}
-			// Subtract recoverable deposit (75%)
-			cumulativeRentExpenses -= config.rentDeposit * 0.75
+		if scenario.IncludeRenting {
+			// Cumulative rental expenses for SELL, net of the recoverable deposit
+			cumulativeRentExpenses := scenario.SellRentingExpenditureAt(period.months)
 
 			rows = append(rows, []string{
 				"NET " + period.label,
@@ -1775,15 +2737,15 @@ func displaySellVsKeepComparison() {
 
 	// Build note text
 	noteText := ""
-	if includeRenting > 0 {
+	if scenario.IncludeRenting {
 		noteText = "Note: 'SELL Cum. Exp' = Total rental costs (deposit + all monthly rent - 75% recoverable deposit).\n\n"
-		noteText += fmt.Sprintf("'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return, minus rental costs (inflated annually at %.1f%%).\n\n", config.investmentReturnRate, config.inflationRate)
+		noteText += fmt.Sprintf("'SELL Net Worth' = Net proceeds from selling today invested at %s return, minus rental costs (inflated annually at %s).\n\n", formatRateSchedule(scenario.InvestmentReturnRates, 0), formatRateSchedule(scenario.InflationRates, 1))
 	} else {
-		noteText = fmt.Sprintf("Note: 'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return with monthly compounding.\n\n", config.investmentReturnRate)
+		noteText = fmt.Sprintf("Note: 'SELL Net Worth' = Net proceeds from selling today invested at %s return with monthly compounding.\n\n", formatRateSchedule(scenario.InvestmentReturnRates, 0))
 	}
-	noteText += fmt.Sprintf("'KEEP Net Position' = Investment value from income (invested at %.0f%% return) minus real out-of-pocket costs (see KEEP Expenses Breakdown for details).\n\n", config.investmentReturnRate)
+	noteText += fmt.Sprintf("'KEEP Net Position' = Investment value from income (invested at %s return) minus real out-of-pocket costs (see KEEP Expenses Breakdown for details).\n\n", formatRateSchedule(scenario.InvestmentReturnRates, 0))
 	noteText += "'KEEP Net Proceeds' = Net proceeds if keeping and selling at that future point, plus net position (see Sale Proceeds Analysis for sale breakdown).\n\n"
 	noteText += "'KEEP - SELL': Positive values mean keeping wins, negative values mean selling wins."
 
-	displayTable("NET WORTH PROJECTIONS: SELL VS KEEP", rows, noteText, false)
+	displayTable("NET WORTH PROJECTIONS: SELL VS KEEP", rows, noteText, false, nil)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"calculator/calc"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		// The suffix is picked after rounding, so values that round up cross into it
+		{999.95, "1.0K"},
+		{999999.5, "1.0M"},
+		{1000, "1.0K"},
+		// Amounts that round to zero drop their sign
+		{-0.01, "0.0"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCurrency(tt.amount); got != tt.want {
+			t.Errorf("formatCurrency(%v) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+// A loan longer than the 30-year projections must still fit the monthly arrays
+func TestLongLoanAmortization(t *testing.T) {
+	scenario := calc.NewScenario(calc.Inputs{
+		PurchasePrice: 400000,
+		Downpayment:   80000,
+		LoanAmount:    320000,
+		AnnualRate:    5,
+		LoanMonths:    480,
+	})
+
+	periods := getPeriods(scenario.LoanMonths, true)
+	last := periods[len(periods)-1]
+	if last.label != "X 40y" || last.months != 480 {
+		t.Fatalf("last period = %q (%d months), want \"X 40y\" (480 months)", last.label, last.months)
+	}
+	if balance := scenario.AmortizationAt(last.months).Balance; math.Abs(balance) > 1e-6 {
+		t.Errorf("balance after %d months = %v, want 0", last.months, balance)
+	}
+
+	output := captureStdout(t, func() { displayAmortizationTable(scenario) })
+	if !strings.Contains(output, "LOAN X 40y") {
+		t.Errorf("amortization table has no row for month 480:\n%s", output)
+	}
+}
+
+// captureStdout returns what f prints to standard output
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	f()
+	w.Close()
+	return <-output
+}
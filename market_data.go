@@ -3,10 +3,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -15,6 +17,40 @@ import (
 
 const marketDataFile = ".rentobuy_market_data.json"
 
+// closeToCloseReturns measures annual returns from the prior year's last close when refreshing market data
+var closeToCloseReturns bool
+
+// returnsCloseToClose reports whether refreshed returns are measured close to close: when asked
+// for, or when the bars are weekly or monthly, as a year's first such bar closes after the year
+// has started
+func returnsCloseToClose() bool {
+	return closeToCloseReturns || marketDataInterval != "1d"
+}
+
+// priceReturnOnly uses the raw close (no dividends) instead of the adjusted close for market returns
+var priceReturnOnly bool
+
+// marketDataInterval is the Yahoo Finance bar size used when refreshing market data. Weekly and
+// monthly bars make the download much smaller than daily ones, but imply close-to-close returns.
+var marketDataInterval = "1d"
+
+// validIntervals are the Yahoo Finance bar sizes market data can be fetched at
+var validIntervals = []string{"1d", "1wk", "1mo"}
+
+// HTTP settings for market data requests, overridable with --http-timeout and --user-agent
+var (
+	httpTimeout = 30 * time.Second
+	userAgent   = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"
+)
+
+// newHTTPClient returns a client using the configured timeout and any HTTP_PROXY/HTTPS_PROXY
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   httpTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	}
+}
+
 // MarketData stores historical annual returns
 type MarketData struct {
 	LastUpdated string             `json:"last_updated"`
@@ -22,6 +58,7 @@ type MarketData struct {
 	QQQ         map[string]float64 `json:"qqq"`    // Year -> Annual return % (Nasdaq 100)
 	VTI         map[string]float64 `json:"vti"`    // Year -> Annual return % (Total Stock Market)
 	BND         map[string]float64 `json:"bnd"`    // Year -> Annual return % (Total Bond Market)
+	PriceReturn bool               `json:"price_return,omitempty"` // Returns exclude dividends
 }
 
 // YahooChartResponse represents the JSON response from Yahoo Finance chart API
@@ -30,33 +67,38 @@ type YahooChartResponse struct {
 		Result []struct {
 			Timestamp []int64 `json:"timestamp"`
 			Indicators struct {
+				Quote []struct {
+					Close []*float64 `json:"close"` // nil where Yahoo returns null
+				} `json:"quote"`
 				Adjclose []struct {
-					Adjclose []float64 `json:"adjclose"`
+					Adjclose []*float64 `json:"adjclose"` // nil where Yahoo returns null
 				} `json:"adjclose"`
 			} `json:"indicators"`
 		} `json:"result"`
 	} `json:"chart"`
 }
 
-// fetchYahooFinanceData fetches historical price data from Yahoo Finance using chart API
-func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]string, error) {
+// fetchYahooFinanceData fetches historical price data from Yahoo Finance using chart API.
+// Prices are the dividend-adjusted close, or the raw close when priceOnly is set, with one
+// bar per interval ("1d", "1wk" or "1mo").
+func fetchYahooFinanceData(ticker string, startDate, endDate time.Time, interval string, priceOnly bool) ([][]string, error) {
 	// Convert to Unix timestamps
 	period1 := startDate.Unix()
 	period2 := endDate.Unix()
 
 	// Build URL using chart API (more reliable than download endpoint)
-	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=1d",
-		ticker, period1, period2)
+	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=%s",
+		ticker, period1, period2, interval)
 
 	// Create request with headers
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	// Make request
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %v", err)
@@ -80,25 +122,47 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 
 	result := chartResp.Chart.Result[0]
 	timestamps := result.Timestamp
-	adjCloses := result.Indicators.Adjclose[0].Adjclose
 
-	if len(timestamps) != len(adjCloses) {
+	// Pick the price series: raw close for price return, adjusted close for total return
+	var closes []*float64
+	column := "Adj Close"
+	if priceOnly {
+		if len(result.Indicators.Quote) == 0 {
+			return nil, fmt.Errorf("no close data returned")
+		}
+		closes = result.Indicators.Quote[0].Close
+		column = "Close"
+	} else {
+		if len(result.Indicators.Adjclose) == 0 {
+			return nil, fmt.Errorf("no adjusted close data returned")
+		}
+		closes = result.Indicators.Adjclose[0].Adjclose
+	}
+
+	if len(timestamps) != len(closes) {
 		return nil, fmt.Errorf("data length mismatch")
 	}
 
-	// Convert to CSV format: Date, Adj Close
-	records := [][]string{{"Date", "Adj Close"}}
+	// Convert to CSV format: Date, Close
+	records := [][]string{{"Date", column}}
 	for i, ts := range timestamps {
+		// Skip days Yahoo has no price for; recording 0 would show up as a -100% return
+		if closes[i] == nil || *closes[i] <= 0 {
+			continue
+		}
 		date := time.Unix(ts, 0).Format("2006-01-02")
-		adjClose := fmt.Sprintf("%.6f", adjCloses[i])
-		records = append(records, []string{date, adjClose})
+		price := fmt.Sprintf("%.6f", *closes[i])
+		records = append(records, []string{date, price})
 	}
 
 	return records, nil
 }
 
-// calculateAnnualReturns calculates annual returns from daily price data
-func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
+// calculateAnnualReturns calculates annual returns from daily, weekly or monthly price data. By default a year's
+// return runs from its first to its last close; with closeToClose it runs from the prior
+// year's last close instead (the conventional definition). Years without a prior year's close
+// in the data (such as the oldest one) are left out then, rather than mixing the definitions.
+func calculateAnnualReturns(records [][]string, closeToClose bool) (map[string]float64, error) {
 	if len(records) < 2 {
 		return nil, fmt.Errorf("insufficient data")
 	}
@@ -125,9 +189,10 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 		}
 		year := date[:4]
 
-		// Parse adjusted close price (column 1)
+		// Parse adjusted close price (column 1), skipping bad data so each year
+		// starts from its first valid price
 		adjClose, err := strconv.ParseFloat(record[1], 64)
-		if err != nil {
+		if err != nil || math.IsNaN(adjClose) || adjClose <= 0 {
 			continue
 		}
 
@@ -143,8 +208,17 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 	// Calculate annual returns
 	returns := make(map[string]float64)
 	for year, data := range yearPrices {
-		if data.firstPrice > 0 {
-			returnPct := ((data.lastPrice - data.firstPrice) / data.firstPrice) * 100
+		basePrice := data.firstPrice
+		if closeToClose {
+			basePrice = 0
+			if y, err := strconv.Atoi(year); err == nil {
+				if prior := yearPrices[strconv.Itoa(y-1)]; prior != nil {
+					basePrice = prior.lastPrice
+				}
+			}
+		}
+		if basePrice > 0 {
+			returnPct := ((data.lastPrice - basePrice) / basePrice) * 100
 			returns[year] = returnPct
 		}
 	}
@@ -152,85 +226,144 @@ func calculateAnnualReturns(records [][]string) (map[string]float64, error) {
 	return returns, nil
 }
 
-// loadMarketData loads cached market data from file
-func loadMarketData() (*MarketData, error) {
-	data, err := os.ReadFile(marketDataFile)
+// tickerCache is one ticker's cached annual returns. Each ticker has its own file and
+// timestamp, so tickers refresh independently and adding one doesn't refetch the rest.
+type tickerCache struct {
+	LastUpdated  string             `json:"last_updated"`
+	PriceReturn  bool               `json:"price_return,omitempty"`   // Returns exclude dividends
+	CloseToClose bool               `json:"close_to_close,omitempty"` // Returns run from the prior year's last close
+	Returns      map[string]float64 `json:"returns"`                  // Year -> Annual return %
+}
+
+// tickerCacheFile returns the cache file path for a ticker
+func tickerCacheFile(symbol string) string {
+	return fmt.Sprintf(".rentobuy_market_%s.json", strings.ToLower(symbol))
+}
+
+// loadTickerCache loads a ticker's cached returns, returning nil if it has no cache file
+func loadTickerCache(symbol string) (*tickerCache, error) {
+	data, err := os.ReadFile(tickerCacheFile(symbol))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &MarketData{
-				VOO: make(map[string]float64),
-				QQQ: make(map[string]float64),
-				VTI: make(map[string]float64),
-				BND: make(map[string]float64),
-			}, nil
+			return nil, nil
 		}
 		return nil, err
 	}
 
-	var md MarketData
-	err = json.Unmarshal(data, &md)
-	if err != nil {
-		return nil, err
-	}
-
-	if md.VOO == nil {
-		md.VOO = make(map[string]float64)
+	var cache tickerCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", tickerCacheFile(symbol), err)
 	}
-	if md.QQQ == nil {
-		md.QQQ = make(map[string]float64)
+	if cache.Returns == nil {
+		cache.Returns = make(map[string]float64)
 	}
-	if md.VTI == nil {
-		md.VTI = make(map[string]float64)
-	}
-	if md.BND == nil {
-		md.BND = make(map[string]float64)
+	return &cache, nil
+}
+
+// saveTickerCache saves a ticker's returns to its cache file
+func saveTickerCache(symbol string, cache *tickerCache) error {
+	cache.LastUpdated = time.Now().Format("2006-01-02")
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
 	}
 
-	return &md, nil
+	return os.WriteFile(tickerCacheFile(symbol), data, 0644)
 }
 
-// saveMarketData saves market data to cache file
-func saveMarketData(md *MarketData) error {
-	md.LastUpdated = time.Now().Format("2006-01-02")
+// needsUpdate reports whether the cached returns are stale: older than a month, missing
+// the current year, or for the other return series or definition
+func (c *tickerCache) needsUpdate(now time.Time) bool {
+	if c.PriceReturn != priceReturnOnly || c.CloseToClose != returnsCloseToClose() {
+		return true
+	}
+	lastUpdate, err := time.Parse("2006-01-02", c.LastUpdated)
+	if err != nil || now.Sub(lastUpdate) > 30*24*time.Hour {
+		return true
+	}
+	_, ok := c.Returns[fmt.Sprintf("%d", now.Year())]
+	return !ok
+}
 
-	data, err := json.MarshalIndent(md, "", "  ")
+// loadLegacyMarketData loads the combined cache file used before tickers were cached
+// separately. It seeds tickers that don't have their own cache file yet.
+func loadLegacyMarketData() (*MarketData, error) {
+	data, err := os.ReadFile(marketDataFile)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	return os.WriteFile(marketDataFile, data, 0644)
+	var md MarketData
+	err = json.Unmarshal(data, &md)
+	if err != nil {
+		return nil, err
+	}
+	return &md, nil
 }
 
-// updateMarketData fetches and updates market data if needed
+// updateMarketData loads each ticker's cached returns, refetching the stale ones, and
+// merges them into one MarketData
 func updateMarketData() (*MarketData, error) {
-	md, err := loadMarketData()
+	legacy, err := loadLegacyMarketData()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load cache: %v", err)
 	}
+	if legacy == nil {
+		legacy = &MarketData{}
+	}
+
+	md := &MarketData{
+		VOO:         make(map[string]float64),
+		QQQ:         make(map[string]float64),
+		VTI:         make(map[string]float64),
+		BND:         make(map[string]float64),
+		PriceReturn: priceReturnOnly,
+	}
+
+	// Define tickers to fetch
+	type tickerSource struct {
+		symbol string
+		target *map[string]float64
+		legacy map[string]float64
+		cache  *tickerCache
+	}
+	tickers := []*tickerSource{
+		{symbol: "VOO", target: &md.VOO, legacy: legacy.VOO},
+		{symbol: "QQQ", target: &md.QQQ, legacy: legacy.QQQ},
+		{symbol: "VTI", target: &md.VTI, legacy: legacy.VTI},
+		{symbol: "BND", target: &md.BND, legacy: legacy.BND},
+	}
 
-	// Check if we need to update
+	// Load each ticker's cache, seeding it from the legacy file if it has none
 	now := time.Now()
-	needsUpdate := false
-
-	// Update if cache is older than 1 month
-	if md.LastUpdated != "" {
-		lastUpdate, err := time.Parse("2006-01-02", md.LastUpdated)
-		if err == nil {
-			if now.Sub(lastUpdate) > 30*24*time.Hour {
-				needsUpdate = true
+	var stale []*tickerSource
+	for _, ticker := range tickers {
+		cache, err := loadTickerCache(ticker.symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s cache: %v", ticker.symbol, err)
+		}
+		if cache == nil {
+			cache = &tickerCache{
+				LastUpdated: legacy.LastUpdated,
+				PriceReturn: legacy.PriceReturn,
+				Returns:     make(map[string]float64),
+			}
+			for year, ret := range ticker.legacy {
+				cache.Returns[year] = ret
 			}
 		}
-	} else {
-		needsUpdate = true
-	}
-
-	// Also update if we don't have current year data
-	currentYear := fmt.Sprintf("%d", now.Year())
-	if _, ok := md.VOO[currentYear]; !ok {
-		needsUpdate = true
+		ticker.cache = cache
+		*ticker.target = cache.Returns
+		if cache.needsUpdate(now) {
+			stale = append(stale, ticker)
+		}
 	}
 
-	if !needsUpdate {
+	if len(stale) == 0 {
 		return md, nil
 	}
 
@@ -240,55 +373,95 @@ func updateMarketData() (*MarketData, error) {
 	startDate := time.Now().AddDate(-11, 0, 0)
 	endDate := time.Now()
 
-	// Define tickers to fetch
-	tickers := []struct {
-		symbol string
-		target *map[string]float64
-	}{
-		{"VOO", &md.VOO},
-		{"QQQ", &md.QQQ},
-		{"VTI", &md.VTI},
-		{"BND", &md.BND},
-	}
-
-	// Fetch each ticker
-	for i, ticker := range tickers {
-		records, err := fetchYahooFinanceData(ticker.symbol, startDate, endDate)
+	// Fetch each stale ticker, showing progress on one line
+	progress := newFetchProgress(len(stale))
+	for i, ticker := range stale {
+		progress.update(i, ticker.symbol)
+		records, err := fetchYahooFinanceData(ticker.symbol, startDate, endDate, marketDataInterval, priceReturnOnly)
 		if err != nil {
+			progress.done()
 			return nil, fmt.Errorf("failed to fetch %s data: %v", ticker.symbol, err)
 		}
 
-		returns, err := calculateAnnualReturns(records)
+		returns, err := calculateAnnualReturns(records, returnsCloseToClose())
 		if err != nil {
+			progress.done()
 			return nil, fmt.Errorf("failed to calculate %s returns: %v", ticker.symbol, err)
 		}
 
-		// Update cache with new data
+		// Update cache with new data, starting over when switching between price and
+		// total return, or between return definitions, so the series don't mix
+		cache := ticker.cache
+		if cache.PriceReturn != priceReturnOnly || cache.CloseToClose != returnsCloseToClose() {
+			cache.Returns = make(map[string]float64)
+			cache.PriceReturn = priceReturnOnly
+			cache.CloseToClose = returnsCloseToClose()
+		}
 		for year, ret := range returns {
-			(*ticker.target)[year] = ret
+			cache.Returns[year] = ret
+		}
+		*ticker.target = cache.Returns
+
+		// Save to cache
+		if err := saveTickerCache(ticker.symbol, cache); err != nil {
+			progress.done()
+			return nil, fmt.Errorf("failed to save %s cache: %v", ticker.symbol, err)
 		}
 
 		// Wait a bit to avoid rate limiting (except on last iteration)
-		if i < len(tickers)-1 {
+		if i < len(stale)-1 {
 			time.Sleep(1 * time.Second)
 		}
 	}
-
-	// Save to cache
-	err = saveMarketData(md)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save cache: %v", err)
-	}
+	progress.done()
 
 	fmt.Println("Market data updated successfully.")
 
 	return md, nil
 }
 
+// fetchProgress shows which ticker is being fetched on a single, rewritten line. It stays
+// quiet when stdout isn't a terminal or colors are off, so piped output is unchanged.
+type fetchProgress struct {
+	total   int
+	enabled bool
+}
+
+func newFetchProgress(total int) *fetchProgress {
+	return &fetchProgress{total: total, enabled: !noColor && terminalWidth() > 0}
+}
+
+// update shows the ticker currently being fetched
+func (p *fetchProgress) update(index int, symbol string) {
+	if !p.enabled {
+		return
+	}
+	fmt.Printf("\r  Fetching %-4s [%s%s] %d/%d", symbol,
+		strings.Repeat("#", index), strings.Repeat(".", p.total-index), index, p.total)
+}
+
+// done clears the progress line
+func (p *fetchProgress) done() {
+	if !p.enabled {
+		return
+	}
+	fmt.Print("\r\033[K")
+}
+
+// MarketAverages holds the 10-year average annual returns (%) of the tracked ETFs
+type MarketAverages struct {
+	VOO     float64
+	QQQ     float64
+	VTI     float64
+	BND     float64
+	Mix6040 float64 // 60% VTI + 40% BND
+}
+
 // calculateMarketAverages calculates 10-year averages for all ETFs
-func calculateMarketAverages(md *MarketData) (voo, qqq, vti, bnd, mix6040 float64) {
+func calculateMarketAverages(md *MarketData) MarketAverages {
+	var avg MarketAverages
 	if md == nil {
-		return 0, 0, 0, 0, 0
+		return avg
 	}
 
 	var vooSum, qqqSum, vtiSum, bndSum float64
@@ -316,16 +489,97 @@ func calculateMarketAverages(md *MarketData) (voo, qqq, vti, bnd, mix6040 float6
 	}
 
 	if count == 0 {
-		return 0, 0, 0, 0, 0
+		return avg
+	}
+
+	avg.VOO = vooSum / float64(count)
+	avg.QQQ = qqqSum / float64(count)
+	avg.VTI = vtiSum / float64(count)
+	avg.BND = bndSum / float64(count)
+	avg.Mix6040 = avg.VTI*0.6 + avg.BND*0.4
+
+	return avg
+}
+
+// RollingAverage is the average annual return over a fixed window of years ending in EndYear
+type RollingAverage struct {
+	EndYear int
+	MarketAverages
+}
+
+// calculateRollingAverages calculates the average returns over every window of consecutive
+// complete years in the dataset, ending at each year that has a full window behind it
+func calculateRollingAverages(md *MarketData, windowYears int) []RollingAverage {
+	var windows []RollingAverage
+	if md == nil || windowYears <= 0 {
+		return windows
+	}
+
+	// Collect complete years where all ETFs have data
+	currentYear := time.Now().Year()
+	complete := make(map[int]bool)
+	for year := range md.VOO {
+		yearInt, err := strconv.Atoi(year)
+		if err != nil || yearInt >= currentYear {
+			continue
+		}
+		_, hasQQQ := md.QQQ[year]
+		_, hasVTI := md.VTI[year]
+		_, hasBND := md.BND[year]
+		if hasQQQ && hasVTI && hasBND {
+			complete[yearInt] = true
+		}
+	}
+
+	endYears := make([]int, 0, len(complete))
+	for year := range complete {
+		endYears = append(endYears, year)
+	}
+	sort.Ints(endYears)
+
+	for _, endYear := range endYears {
+		var vooSum, qqqSum, vtiSum, bndSum float64
+		full := true
+		for year := endYear - windowYears + 1; year <= endYear; year++ {
+			if !complete[year] {
+				full = false
+				break
+			}
+			key := strconv.Itoa(year)
+			vooSum += md.VOO[key]
+			qqqSum += md.QQQ[key]
+			vtiSum += md.VTI[key]
+			bndSum += md.BND[key]
+		}
+		if !full {
+			continue
+		}
+
+		n := float64(windowYears)
+		w := RollingAverage{EndYear: endYear}
+		w.VOO = vooSum / n
+		w.QQQ = qqqSum / n
+		w.VTI = vtiSum / n
+		w.BND = bndSum / n
+		w.Mix6040 = w.VTI*0.6 + w.BND*0.4
+		windows = append(windows, w)
 	}
 
-	voo = vooSum / float64(count)
-	qqq = qqqSum / float64(count)
-	vti = vtiSum / float64(count)
-	bnd = bndSum / float64(count)
-	mix6040 = vti*0.6 + bnd*0.4
+	return windows
+}
 
-	return
+// median returns the middle value of the given returns, averaging the two middle values for even counts
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
 }
 
 // displayMarketData shows historical returns and averages
@@ -347,6 +601,7 @@ func displayMarketData(md *MarketData) {
 	}
 
 	var vooSum, qqqSum, vtiSum, bndSum float64
+	var vooRets, qqqRets, vtiRets, bndRets, mixRets []float64
 	count := 0
 
 	for _, year := range years {
@@ -362,6 +617,11 @@ func displayMarketData(md *MarketData) {
 			qqqSum += qqqRet
 			vtiSum += vtiRet
 			bndSum += bndRet
+			vooRets = append(vooRets, vooRet)
+			qqqRets = append(qqqRets, qqqRet)
+			vtiRets = append(vtiRets, vtiRet)
+			bndRets = append(bndRets, bndRet)
+			mixRets = append(mixRets, mix6040)
 			count++
 		}
 
@@ -386,27 +646,39 @@ func displayMarketData(md *MarketData) {
 			fmt.Sprintf("%.2f%%", bndSum/float64(count)),
 			fmt.Sprintf("%.2f%%", avgMix),
 		})
+		rows = append(rows, []string{
+			"MRKT Median",
+			fmt.Sprintf("%.2f%%", median(vooRets)),
+			fmt.Sprintf("%.2f%%", median(qqqRets)),
+			fmt.Sprintf("%.2f%%", median(vtiRets)),
+			fmt.Sprintf("%.2f%%", median(bndRets)),
+			fmt.Sprintf("%.2f%%", median(mixRets)),
+		})
+	}
+	summaryRows := 0
+	if count > 0 {
+		summaryRows = 2
 	}
 
 	// Display using same pattern as other tables
-	re := lipgloss.NewRenderer(os.Stdout)
-	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
-	headerStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiCyan).Bold(true)
-	rowStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiAdaptiveText)
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	headerStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Accent).Bold(true)
+	rowStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Text)
 
 	// Print title
 	fmt.Println()
-	fmt.Println(titleStyle.Render("MARKET DATA"))
+	fmt.Println(titleStyle.Render("MARKET DATA" + returnSeriesLabel(md)))
 
 	// Create table
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
-		BorderStyle(re.NewStyle().Foreground(MonokaiBorder)).
+		BorderStyle(re.NewStyle().Foreground(activeTheme.Border)).
 		Rows(rows...).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			var style lipgloss.Style
-			if row == 0 || row == len(rows)-1 {
-				// Header row and average row (last row)
+			if row == 0 || row >= len(rows)-summaryRows {
+				// Header row and average/median rows (last rows)
 				style = headerStyle
 			} else {
 				style = rowStyle
@@ -421,4 +693,62 @@ func displayMarketData(md *MarketData) {
 		})
 
 	fmt.Println(t)
+
+	displayRollingAverages(md)
+}
+
+// returnSeriesLabel marks market tables built from price-only returns
+func returnSeriesLabel(md *MarketData) string {
+	if md.PriceReturn {
+		return " (PRICE RETURN, EXCLUDING DIVIDENDS)"
+	}
+	return ""
+}
+
+// displayRollingAverages shows the rolling 10-year average returns ending each year, so a
+// single trailing window isn't taken as the typical return
+func displayRollingAverages(md *MarketData) {
+	windows := calculateRollingAverages(md, 10)
+	if len(windows) == 0 {
+		return
+	}
+
+	rows := [][]string{
+		{"10Y Ending", "VOO", "QQQ", "VTI", "BND", "60/40 VTI/BND"},
+	}
+	for _, w := range windows {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", w.EndYear),
+			fmt.Sprintf("%.2f%%", w.VOO),
+			fmt.Sprintf("%.2f%%", w.QQQ),
+			fmt.Sprintf("%.2f%%", w.VTI),
+			fmt.Sprintf("%.2f%%", w.BND),
+			fmt.Sprintf("%.2f%%", w.Mix6040),
+		})
+	}
+
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	headerStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Accent).Bold(true)
+	rowStyle := re.NewStyle().Padding(0, 1).Foreground(activeTheme.Text)
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("ROLLING 10-YEAR AVERAGES" + returnSeriesLabel(md)))
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(re.NewStyle().Foreground(activeTheme.Border)).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := rowStyle
+			if row == 0 {
+				style = headerStyle
+			}
+			if col > 0 {
+				style = style.Align(lipgloss.Right)
+			}
+			return style
+		})
+
+	fmt.Println(t)
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateAnnualReturnsSkipsBadPrices(t *testing.T) {
+	records := [][]string{
+		{"Date", "Adj Close"},
+		// 2020 opens with bad prices; its return starts from the first valid one
+		{"2020-01-02", ""},
+		{"2020-01-03", "abc"},
+		{"2020-01-06", "100"},
+		{"2020-06-01", ""},
+		{"2020-12-31", "110"},
+		// 2021 has no valid price at all
+		{"2021-01-04", "0"},
+		{"2021-06-01", "NaN"},
+		{"2021-12-31", ""},
+		{"2022-01-03", "120"},
+		{"2022-12-30", "132"},
+		{"2023-01-03", "140"},
+		{"2023-12-29", "145.2"},
+	}
+
+	tests := []struct {
+		closeToClose bool
+		want         map[string]float64
+	}{
+		{false, map[string]float64{"2020": 10, "2022": 10, "2023": 3.714285714285714}},
+		// Close to close, the oldest year and 2022 (after the year without a valid close)
+		// have no base, so only 2023 is measured, from 2022's last close
+		{true, map[string]float64{"2023": 10}},
+	}
+
+	for _, tt := range tests {
+		returns, err := calculateAnnualReturns(records, tt.closeToClose)
+		if err != nil {
+			t.Fatalf("closeToClose=%v: %v", tt.closeToClose, err)
+		}
+		if len(returns) != len(tt.want) {
+			t.Errorf("closeToClose=%v: got returns for %v, want %v", tt.closeToClose, returns, tt.want)
+		}
+		for year, pct := range tt.want {
+			if got, ok := returns[year]; !ok || math.Abs(got-pct) > 1e-9 {
+				t.Errorf("closeToClose=%v: return for %s = %v, want %v", tt.closeToClose, year, got, pct)
+			}
+		}
+	}
+}
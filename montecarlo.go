@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"calculator/calc"
+)
+
+// Monte Carlo settings from the command line (0 trials = not shown)
+var monteCarloTrials int
+var monteCarloSeed uint64 = 1
+var returnStdev = 15.0      // Yearly standard deviation of the investment return (%)
+var appreciationStdev = 5.0 // Yearly standard deviation of the appreciation rate (%)
+var monteCarloDraw = "gaussian"
+
+// monteCarloCorrelation is the correlation between each year's investment return and
+// appreciation shocks in --montecarlo. The market data has no home price history to measure
+// it from, so by default the two are drawn independently.
+var monteCarloCorrelation = 0.0
+
+// monteCarloDraws are the ways --montecarlo can draw each year's investment return
+var monteCarloDraws = []string{"gaussian", "bootstrap"}
+
+// monteCarloPercentiles are the percentiles of the RENT - BUY difference shown across trials
+var monteCarloPercentiles = []float64{5, 25, 50, 75, 95}
+
+// rateDrawer returns one trial's yearly appreciation and investment return schedules over the given years
+type rateDrawer func(inputs calc.Inputs, years int, rng *rand.Rand) (appreciation, returns []float64)
+
+// appreciationShocks draws each year's independent standard normal shock to the appreciation
+// rate (none for dollar appreciation, which is left as entered)
+func appreciationShocks(inputs calc.Inputs, years int, rng *rand.Rand) []float64 {
+	if inputs.AppreciationInDollars {
+		return nil
+	}
+	shocks := make([]float64, years)
+	for year := range shocks {
+		shocks[year] = rng.NormFloat64()
+	}
+	return shocks
+}
+
+// correlatedAppreciation returns each year's input appreciation rate plus its shock, mixed with
+// the year's standardized investment return shock so the two have monteCarloCorrelation (the
+// Cholesky factor of their 2x2 correlation matrix). Rates are kept above -99% so the value
+// can't go negative.
+func correlatedAppreciation(inputs calc.Inputs, shocks, returnShocks []float64) []float64 {
+	if inputs.AppreciationInDollars {
+		return inputs.AppreciationRates
+	}
+	rho := monteCarloCorrelation
+	independent := math.Sqrt(1 - rho*rho)
+	appreciation := make([]float64, len(shocks))
+	for year := range appreciation {
+		shock := rho*returnShocks[year] + independent*shocks[year]
+		appreciation[year] = max(scheduleRate(inputs.AppreciationRates, year)+shock*appreciationStdev, -99)
+	}
+	return appreciation
+}
+
+// gaussianRates draws each year's investment return as the input rate plus a normally
+// distributed shock
+func gaussianRates(inputs calc.Inputs, years int, rng *rand.Rand) (appreciation, returns []float64) {
+	shocks := appreciationShocks(inputs, years, rng)
+	returns = make([]float64, years)
+	returnShocks := make([]float64, years)
+	for year := range returns {
+		returnShocks[year] = rng.NormFloat64()
+		returns[year] = max(scheduleRate(inputs.InvestmentReturnRates, year)+returnShocks[year]*returnStdev, -99)
+	}
+	return correlatedAppreciation(inputs, shocks, returnShocks), returns
+}
+
+// bootstrapRates draws each year's investment return by resampling a whole historical year
+// (with replacement), keeping the fat tails a normal distribution misses. The history is
+// re-centered on the input rate, so only its spread is used, not its average.
+// Appreciation is correlated with how far the drawn year is from the historical mean.
+func bootstrapRates(history []float64) rateDrawer {
+	mean := 0.0
+	for _, r := range history {
+		mean += r
+	}
+	mean /= float64(len(history))
+	stdev := 0.0
+	for _, r := range history {
+		stdev += (r - mean) * (r - mean)
+	}
+	stdev = math.Sqrt(stdev / float64(len(history)))
+
+	return func(inputs calc.Inputs, years int, rng *rand.Rand) (appreciation, returns []float64) {
+		shocks := appreciationShocks(inputs, years, rng)
+		returns = make([]float64, years)
+		returnShocks := make([]float64, years)
+		for year := range returns {
+			deviation := history[rng.IntN(len(history))] - mean
+			if stdev > 0 {
+				returnShocks[year] = deviation / stdev
+			}
+			returns[year] = max(scheduleRate(inputs.InvestmentReturnRates, year)+deviation, -99)
+		}
+		return correlatedAppreciation(inputs, shocks, returnShocks), returns
+	}
+}
+
+// historicalReturns returns the yearly S&P 500 (VOO) returns of the complete years in the
+// market data, oldest first
+func historicalReturns(md *MarketData) (years []int, returns []float64) {
+	if md == nil {
+		return nil, nil
+	}
+	currentYear := time.Now().Year()
+	for year := range md.VOO {
+		if y, err := strconv.Atoi(year); err == nil && y < currentYear {
+			years = append(years, y)
+		}
+	}
+	sort.Ints(years)
+	for _, y := range years {
+		returns = append(returns, md.VOO[strconv.Itoa(y)])
+	}
+	return years, returns
+}
+
+// scheduleRate returns the rate of a yearly schedule for the given 0-based year, where the
+// last rate applies to all remaining years
+func scheduleRate(rates []float64, year int) float64 {
+	if len(rates) == 0 {
+		return 0
+	}
+	return rates[min(year, len(rates)-1)]
+}
+
+// trialOutcome is one Monte Carlo trial's RENT - BUY difference at the horizon
+type trialOutcome struct {
+	trial      int
+	difference float64
+}
+
+// runMonteCarlo projects the inputs with the given number of randomly drawn rate schedules and
+// returns each trial's RENT - BUY difference at the horizon, sorted ascending. Trials run on a
+// worker per CPU; each has its own RNG seeded from the seed and the trial number, so the
+// results don't depend on how the trials are scheduled.
+func runMonteCarlo(inputs calc.Inputs, trials, horizonMonths int, seed uint64, draw rateDrawer) []float64 {
+	years := (horizonMonths + 11) / 12
+
+	jobs := make(chan int)
+	outcomes := make(chan trialOutcome)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), trials) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trial := range jobs {
+				rng := rand.New(rand.NewPCG(seed, uint64(trial)))
+				trialInputs := inputs
+				trialInputs.AppreciationRates, trialInputs.InvestmentReturnRates = draw(inputs, years, rng)
+				scenario := calc.NewScenario(trialInputs)
+				outcomes <- trialOutcome{trial, scenario.RentingNetWorthAt(horizonMonths).NetWorth - scenario.NetWorthAt(horizonMonths).NetWorth}
+			}
+		}()
+	}
+
+	go func() {
+		for trial := range trials {
+			jobs <- trial
+		}
+		close(jobs)
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	differences := make([]float64, trials)
+	for outcome := range outcomes {
+		differences[outcome.trial] = outcome.difference
+	}
+
+	sort.Float64s(differences)
+	return differences
+}
+
+// percentile returns the p-th percentile (0-100) of the sorted values, by nearest rank
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(p/100*float64(len(sorted)-1) + 0.5)
+	return sorted[min(max(index, 0), len(sorted)-1)]
+}
+
+// displayMonteCarlo shows the spread of the RENT - BUY difference at the horizon across
+// Monte Carlo trials, and how often buying comes out ahead
+func displayMonteCarlo(scenario *calc.Scenario, trials int, md *MarketData) {
+	horizon := projectionHorizon()
+
+	draw := gaussianRates
+	returnSpread := fmt.Sprintf("with a %.1f%% standard deviation", returnStdev)
+	if monteCarloDraw == "bootstrap" {
+		years, history := historicalReturns(md)
+		if len(history) < 2 {
+			fmt.Println()
+			fmt.Println("Monte Carlo bootstrap needs at least two complete years of market data.")
+			return
+		}
+		draw = bootstrapRates(history)
+		returnSpread = fmt.Sprintf("by resampling S&P 500 (VOO) years %d-%d", years[0], years[len(years)-1])
+	}
+	differences := runMonteCarlo(scenario.Inputs, trials, horizon, monteCarloSeed, draw)
+
+	rows := [][]string{{"Percentile", "RENT - BUY"}}
+	for _, p := range monteCarloPercentiles {
+		rows = append(rows, []string{fmt.Sprintf("P%.0f", p), formatCurrency(percentile(differences, p))})
+	}
+
+	buyingWins := 0
+	for _, difference := range differences {
+		if difference < 0 {
+			buyingWins++
+		}
+	}
+
+	notes := fmt.Sprintf("Note: %d trials at %s (seed %d). Each year's investment return is drawn around %s %s",
+		trials, formatHorizon(horizon), monteCarloSeed, formatRateSchedule(scenario.InvestmentReturnRates, 1), returnSpread)
+	if scenario.AppreciationInDollars {
+		notes += "; dollar appreciation is kept as entered."
+	} else {
+		notes += fmt.Sprintf(", and appreciation around %s with a %.1f%% standard deviation", formatRateSchedule(scenario.AppreciationRates, 1), appreciationStdev)
+		if monteCarloCorrelation != 0 {
+			notes += fmt.Sprintf(", correlated with the return at %.2f", monteCarloCorrelation)
+		}
+		notes += "."
+	}
+	notes += " 'RENT - BUY': Positive values mean renting wins, negative values mean buying wins."
+	displayTable(fmt.Sprintf("MONTE CARLO OUTCOMES AT %s", strings.ToUpper(formatHorizon(horizon))), rows, notes, false, nil)
+
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(activeTheme.Accent)
+	fmt.Printf("  %s: %.1f%%\n", labelStyle.Render("Probability buying wins"), float64(buyingWins)/float64(len(differences))*100)
+}
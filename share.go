@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// encodeShareToken packs the inputs into a compact, URL-safe token: gzipped JSON in
+// unpadded base64url
+func encodeShareToken(values map[string]string) (string, error) {
+	// Empty fields default to 0 anyway, so leave them out to keep the token short
+	compact := make(map[string]string)
+	for key, value := range values {
+		if strings.TrimSpace(value) != "" {
+			compact[key] = value
+		}
+	}
+	data, err := json.Marshal(compact)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeShareToken unpacks a token from encodeShareToken into inputs
+func decodeShareToken(token string) (map[string]string, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid token: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid token: %v", err)
+	}
+	defer zr.Close()
+
+	// Tokens are small; anything much larger isn't one of ours
+	data, err := io.ReadAll(io.LimitReader(zr, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid token: %v", err)
+	}
+	return readInputs(bytes.NewReader(data))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"calculator/calc"
+)
+
+// solveTargets lists the inputs --solve can solve for
+var solveTargets = []string{"appreciation", "rent", "return"}
+
+// validSolveTarget reports whether --solve names a supported input
+func validSolveTarget(target string) bool {
+	for _, t := range solveTargets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// formatHorizon formats a horizon in months for sentences, e.g. "10 years"
+func formatHorizon(months int) string {
+	if months%12 == 0 {
+		if months == 12 {
+			return "1 year"
+		}
+		return fmt.Sprintf("%d years", months/12)
+	}
+	return fmt.Sprintf("%d months", months)
+}
+
+// runSolver solves for the breakeven value of the target input at the given horizon
+// and prints the result
+func runSolver(target string, inputs calc.Inputs, months int, isSellVsKeep bool) {
+	if isSellVsKeep {
+		fmt.Println("Error: --solve applies to the BUY vs RENT scenario only")
+		return
+	}
+
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(activeTheme.Primary).Bold(true)
+	fmt.Println()
+	fmt.Println(titleStyle.Render("BREAKEVEN SOLVER"))
+
+	horizon := formatHorizon(months)
+	switch target {
+	case "appreciation":
+		rate, ok := calc.BreakevenAppreciation(inputs, months)
+		if !ok {
+			fmt.Printf("  Buying and renting don't break even at %s for any appreciation between %.0f%% and %.0f%%/yr.\n",
+				horizon, calc.MinSolveAppreciation, calc.MaxSolveAppreciation)
+			return
+		}
+		fmt.Printf("  You need ~%.1f%%/yr appreciation for buying to break even at %s.\n", rate, horizon)
+	case "rent":
+		rent, ok := calc.BreakevenRent(inputs, months)
+		if !ok {
+			fmt.Printf("  Renting and buying don't break even at %s for any monthly rent.\n", horizon)
+			return
+		}
+		fmt.Printf("  Renting is the better deal below ~%s/mo rent over %s (current rent: %s/mo).\n",
+			formatCurrency(rent), horizon, formatCurrency(inputs.MonthlyRent))
+	case "return":
+		rate, ok := calc.BreakevenReturn(inputs, months)
+		if !ok {
+			fmt.Printf("  Renting and buying don't break even at %s for any investment return between %.0f%% and %.0f%%/yr.\n",
+				horizon, calc.MinSolveReturn, calc.MaxSolveReturn)
+			return
+		}
+		fmt.Printf("  Your investments need ~%.1f%%/yr return for renting to match buying at %s.\n", rate, horizon)
+	}
+}
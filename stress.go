@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"calculator/calc"
+)
+
+// stressScenario is a historical sequence of yearly home appreciation and equity returns (%)
+// that replaces the first years of the input schedules
+type stressScenario struct {
+	Description  string
+	Appreciation []float64
+	Returns      []float64
+}
+
+// stressScenarios are the named presets for --stress, with approximate US yearly figures:
+// S&P 500 total returns and national home price changes
+var stressScenarios = map[string]stressScenario{
+	"2008": {
+		Description:  "2008 financial crisis: homes -18%, equities -37% in year 1, then a slow recovery",
+		Appreciation: []float64{-18, -3, -4, -4, 7},
+		Returns:      []float64{-37, 26.5, 15.1, 2.1, 16},
+	},
+	"2000": {
+		Description:  "2000 dot-com bust: three down years for equities while homes kept rising",
+		Appreciation: []float64{9, 7, 8, 10},
+		Returns:      []float64{-9.1, -11.9, -22.1, 28.7},
+	},
+	"1973": {
+		Description:  "1973 stagflation: equities -15% and -26% as inflation ran hot",
+		Appreciation: []float64{10, 11, 9},
+		Returns:      []float64{-14.7, -26.5, 37.2},
+	},
+}
+
+// stressNames returns the --stress preset names, sorted
+func stressNames() []string {
+	var names []string
+	for name := range stressScenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyStress puts the named sequence in front of the appreciation and investment return
+// schedules, so the input rates resume once it ends. Dollar appreciation is left as entered.
+func applyStress(inputs calc.Inputs, name string) (calc.Inputs, error) {
+	stress, ok := stressScenarios[name]
+	if !ok {
+		return inputs, fmt.Errorf("unknown stress scenario %q (expected one of: %v)", name, stressNames())
+	}
+	if !inputs.AppreciationInDollars {
+		inputs.AppreciationRates = slices.Concat(stress.Appreciation, inputs.AppreciationRates)
+	}
+	inputs.InvestmentReturnRates = slices.Concat(stress.Returns, inputs.InvestmentReturnRates)
+	return inputs, nil
+}
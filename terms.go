@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"calculator/calc"
+)
+
+// runTermComparison runs the buying projection once per loan term and shows the total
+// interest and net worth of each at the horizon
+func runTermComparison(inputs calc.Inputs, terms []int, horizonMonths int, isSellVsKeep bool) {
+	if isSellVsKeep {
+		fmt.Println("Error: --compare-terms applies to the BUY vs RENT scenario only")
+		return
+	}
+	if inputs.LoanAmount <= 0 {
+		fmt.Println("Error: --compare-terms needs a loan amount")
+		return
+	}
+
+	rows := [][]string{
+		{"Term", "Monthly Payment", "Total Interest", "Buying NW", "Renting NW", "RENT - BUY"},
+	}
+
+	bestTerm := 0
+	bestNetWorth := 0.0
+	for _, term := range terms {
+		termInputs := inputs
+		termInputs.LoanMonths = term
+		termInputs.ProjectionMonths = max(inputs.ProjectionMonths, horizonMonths)
+		// A recast outside the new term doesn't apply
+		if termInputs.RecastMonth >= term {
+			termInputs.RecastMonth = 0
+			termInputs.RecastAmount = 0
+		}
+		scenario := calc.NewScenario(termInputs)
+
+		buying := scenario.NetWorthAt(horizonMonths)
+		renting := scenario.RentingNetWorthAt(horizonMonths)
+		if bestTerm == 0 || buying.NetWorth > bestNetWorth {
+			bestTerm = term
+			bestNetWorth = buying.NetWorth
+		}
+
+		rows = append(rows, []string{
+			"TERM " + formatPeriodLabel(term),
+			formatCurrency(scenario.MonthlyLoanPayment),
+			formatCurrency(scenario.AmortizationAt(term).CumulativeInterest),
+			formatCurrency(buying.NetWorth),
+			formatCurrency(renting.NetWorth),
+			formatCurrency(renting.NetWorth - buying.NetWorth),
+		})
+	}
+
+	horizon := formatHorizon(horizonMonths)
+	noteText := fmt.Sprintf("Note: 'Total Interest' = Interest over the full loan term. Net worth columns are at %s. 'Renting NW' invests each term's buying-minus-renting savings, so a shorter term's higher payments are compared against renting, not invested on the buying side.", horizon)
+	displayTable(fmt.Sprintf("LOAN TERM COMPARISON (%s)", horizon), rows, noteText, false, nil)
+
+	re := newRenderer()
+	textStyle := re.NewStyle().Foreground(activeTheme.Text)
+	fmt.Println("  " + textStyle.Render(fmt.Sprintf("A %s loan yields the highest buying net worth at %s.", strings.TrimSpace(formatPeriodLabel(bestTerm)), horizon)))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"calculator/calc"
+)
+
+// writeTimeline writes a month-by-month CSV of both BUY vs RENT scenarios up to the projection horizon
+func writeTimeline(path string, scenario *calc.Scenario) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create timeline: %v", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"month", "buying_cost", "renting_cost", "loan_balance", "asset_value", "buying_net_worth", "renting_net_worth"})
+
+	formatRaw := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	for month := 1; month <= projectionHorizon(); month++ {
+		buying := scenario.NetWorthAt(month)
+		w.Write([]string{
+			strconv.Itoa(month),
+			formatRaw(scenario.BuyingCostAt(month)),
+			formatRaw(scenario.RentingCostAt(month)),
+			formatRaw(scenario.AmortizationAt(month).Balance),
+			formatRaw(buying.AssetValue),
+			formatRaw(buying.NetWorth),
+			formatRaw(scenario.RentingNetWorthAt(month).NetWorth),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write timeline: %v", err)
+	}
+	return nil
+}
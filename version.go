@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build details, overridable at build time with
+// -ldflags "-X main.commit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%d)"
+var (
+	commit    = ""
+	buildDate = ""
+)
+
+// versionString reports the module version, git commit, and build date. Values not set
+// via ldflags fall back to the VCS stamps Go embeds in the binary.
+func versionString() string {
+	version := "(devel)"
+	vcsRevision, vcsTime, modified := "", "", false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" {
+			version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				vcsRevision = setting.Value
+			case "vcs.time":
+				vcsTime = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+	}
+
+	rev := commit
+	if rev == "" {
+		rev = vcsRevision
+		if len(rev) > 12 {
+			rev = rev[:12]
+		}
+		if rev != "" && modified {
+			rev += "-dirty"
+		}
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+
+	date := buildDate
+	if date == "" {
+		date = vcsTime
+	}
+	if date == "" {
+		date = "unknown"
+	}
+
+	return fmt.Sprintf("rentobuy %s (commit %s, built %s)", version, rev, date)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"calculator/calc"
+)
+
+// lastRunFile keeps the inputs of the last run, however they were given (form, --defaults,
+// key=value arguments, --stdin or --import), and its outcome flags, for --vs-last
+const lastRunFile = ".rentobuy_last_run.json"
+
+// runSettings are a run's inputs together with the flags that change its outcome
+type runSettings struct {
+	Inputs map[string]string `json:"inputs"`
+	Flags  map[string]string `json:"flags,omitempty"` // Flag name without the dashes -> value
+}
+
+// outcomeFlags returns the flags set for this run that change the scenario itself, rather than
+// just how it's shown: --periods (the projection length), --sdlt-bands and --stress
+func outcomeFlags(periods, stress string) map[string]string {
+	flags := make(map[string]string)
+	for name, value := range map[string]string{"periods": periods, "sdlt-bands": sdltBands, "stress": stress} {
+		if value = strings.TrimSpace(value); value != "" {
+			flags[name] = value
+		}
+	}
+	return flags
+}
+
+// loadLastRun loads the settings of the last run
+func loadLastRun() runSettings {
+	var run runSettings
+	data, err := os.ReadFile(lastRunFile)
+	if err != nil {
+		return run
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return runSettings{}
+	}
+	return run
+}
+
+// saveLastRun saves the settings of this run for the next run to compare against
+func saveLastRun(run runSettings) {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return
+	}
+	os.WriteFile(lastRunFile, data, 0644)
+}
+
+// parseRun parses a run's inputs under its own flags, restoring this run's flags afterwards
+func parseRun(run runSettings, isSellVsKeep bool) (calc.Inputs, error) {
+	savedPeriods, savedBands := customPeriods, sdltBands
+	defer func() { customPeriods, sdltBands = savedPeriods, savedBands }()
+
+	customPeriods = nil
+	if periods := run.Flags["periods"]; periods != "" {
+		parsed, err := parsePeriods(periods)
+		if err != nil {
+			return calc.Inputs{}, fmt.Errorf("invalid --periods: %v", err)
+		}
+		customPeriods = parsed
+	}
+	sdltBands = run.Flags["sdlt-bands"]
+
+	inputs, err := parseConfig(run.Inputs, isSellVsKeep)
+	if err != nil {
+		return inputs, err
+	}
+	if stress := run.Flags["stress"]; stress != "" {
+		return applyStress(inputs, stress)
+	}
+	return inputs, nil
+}
+
+// changedInputs returns the keys whose values differ between the two input sets, in form order
+func changedInputs(last, current map[string]string) []string {
+	order := make(map[string]int)
+	for i, field := range NewFormModel(map[string]string{}, nil).fields {
+		order[field.Key] = i
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, values := range []map[string]string{current, last} {
+		for key := range values {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if strings.TrimSpace(last[key]) != strings.TrimSpace(current[key]) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Slice(keys, func(a, b int) bool {
+		oa, okA := order[keys[a]]
+		ob, okB := order[keys[b]]
+		if okA != okB {
+			return okA // Known form fields first
+		}
+		if oa != ob {
+			return oa < ob
+		}
+		return keys[a] < keys[b]
+	})
+	return keys
+}
+
+// ownershipAdvantage returns how far owning (BUY or KEEP) is ahead of the alternative after the given months
+func ownershipAdvantage(scenario *calc.Scenario, months int, isSellVsKeep bool) float64 {
+	if isSellVsKeep {
+		return scenario.KeepNetWorthAt(months) - scenario.SellNetWorthAt(months)
+	}
+	return scenario.NetWorthAt(months).NetWorth - scenario.RentingNetWorthAt(months).NetWorth
+}
+
+// displayVsLast shows which inputs and flags changed since the last run and how that moved the outcome
+func displayVsLast(current, lastRun runSettings, scenario *calc.Scenario, isSellVsKeep bool) {
+	fmt.Println()
+	last := lastRun.Inputs
+	if len(last) == 0 {
+		fmt.Println("No previous run to compare against yet.")
+		return
+	}
+	lastSellVsKeep, _ := getFloatValue(last, "scenario_sell_vs_keep")
+	if (lastSellVsKeep > 0) != isSellVsKeep {
+		fmt.Println("The last run used the other scenario, so there's nothing to compare against.")
+		return
+	}
+	lastInputs, err := parseRun(lastRun, isSellVsKeep)
+	if err != nil {
+		fmt.Println("Could not compare against the last run:", err)
+		return
+	}
+	// Project the last run as far as this one, so both are compared at the same horizons
+	lastInputs.ProjectionMonths = max(lastInputs.ProjectionMonths, scenario.ProjectionMonths)
+	lastScenario := calc.NewScenario(lastInputs)
+
+	// Flags are compared as --name keys, listed after the inputs
+	withFlags := func(run runSettings) map[string]string {
+		values := make(map[string]string, len(run.Inputs)+len(run.Flags))
+		for key, value := range run.Inputs {
+			values[key] = value
+		}
+		for name, value := range run.Flags {
+			values["--"+name] = value
+		}
+		return values
+	}
+	last = withFlags(lastRun)
+	currentValues := withFlags(current)
+
+	keys := changedInputs(last, currentValues)
+	if len(keys) == 0 {
+		fmt.Println("No inputs or flags changed since the last run.")
+		return
+	}
+
+	labels := make(map[string]string)
+	for key, field := range NewFormModel(map[string]string{}, nil).fieldsMap {
+		labels[key] = field.Label
+	}
+	labelOf := func(key string) string {
+		if label, ok := labels[key]; ok {
+			return label
+		}
+		return key
+	}
+	valueOf := func(values map[string]string, key string) string {
+		if value := strings.TrimSpace(values[key]); value != "" {
+			return value
+		}
+		return "-"
+	}
+
+	rows := [][]string{{"Input", "Last Run", "This Run"}}
+	for _, key := range keys {
+		rows = append(rows, []string{labelOf(key), valueOf(last, key), valueOf(currentValues, key)})
+	}
+	displayTable("CHANGES SINCE LAST RUN", rows, "", false, nil)
+
+	owning, alternative, subject := "BUY", "RENT", "buying"
+	if isSellVsKeep {
+		owning, alternative, subject = "KEEP", "SELL", "keeping"
+	}
+
+	months := []int{120}
+	if horizon := projectionHorizon(); horizon != 120 {
+		months = append(months, horizon)
+	}
+	rows = [][]string{{"Horizon", owning + " - " + alternative + " Last", "This Run", "Change"}}
+	var summaries []string
+	for _, m := range months {
+		before := ownershipAdvantage(lastScenario, m, isSellVsKeep)
+		after := ownershipAdvantage(scenario, m, isSellVsKeep)
+		change := after - before
+		rows = append(rows, []string{formatHorizon(m), formatCurrency(before), formatCurrency(after), formatCurrency(change)})
+
+		verb := "improve"
+		if change < 0 {
+			verb = "worsen"
+		}
+		what := "these changes"
+		if len(keys) == 1 {
+			what = fmt.Sprintf("changing %s from %s to %s", labelOf(keys[0]), valueOf(last, keys[0]), valueOf(currentValues, keys[0]))
+			verb += "s"
+		}
+		summaries = append(summaries, fmt.Sprintf("At %s, %s %s %s by %s.", formatHorizon(m), what, verb, subject, formatCurrency(math.Abs(change))))
+	}
+
+	notes := "Note: " + strings.Join(summaries, " ")
+	displayTable("OUTCOME VS LAST RUN", rows, notes, false, nil)
+}